@@ -0,0 +1,92 @@
+// Package api serves gator's data as a versioned JSON API over HTTP.
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gator/internal/database"
+	"gator/rss"
+	"log"
+	"net/http"
+)
+
+// UserHeader names the user a request acts for. Gator has no passwords,
+// so the API trusts this header the same way the CLI trusts the config
+// file and must only be exposed to trusted clients.
+const UserHeader = "X-Gator-User"
+
+type Server struct {
+	DB database.Querier
+}
+
+func New(db database.Querier) *Server {
+	return &Server{DB: db}
+}
+
+// Handler routes the v1 API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users", s.handleListUsers)
+	mux.HandleFunc("POST /v1/users", s.handleCreateUser)
+	mux.HandleFunc("GET /v1/feeds", s.handleListFeeds)
+	mux.HandleFunc("POST /v1/feeds", s.authenticated(s.handleCreateFeed))
+	mux.HandleFunc("GET /v1/follows", s.authenticated(s.handleListFollows))
+	mux.HandleFunc("POST /v1/follows", s.authenticated(s.handleFollow))
+	mux.HandleFunc("DELETE /v1/follows", s.authenticated(s.handleUnfollow))
+	mux.HandleFunc("GET /v1/posts", s.authenticated(s.handleListPosts))
+	mux.HandleFunc("GET /v1/users/{name}/feed.rss", s.handleUserFeed("application/rss+xml; charset=utf-8", rss.WriteRSS))
+	mux.HandleFunc("GET /v1/users/{name}/feed.atom", s.handleUserFeed("application/atom+xml; charset=utf-8", rss.WriteAtom))
+	return mux
+}
+
+type authedHandler func(w http.ResponseWriter, r *http.Request, user database.User)
+
+// authenticated resolves the user named by UserHeader before calling
+// handler, the API counterpart of cmd.MiddlewareLoggedIn.
+func (s *Server) authenticated(handler authedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.Header.Get(UserHeader)
+		if name == "" {
+			respondWithError(w, http.StatusUnauthorized, "missing "+UserHeader+" header", nil)
+			return
+		}
+		user, err := s.DB.GetUser(r.Context(), name)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "unknown user", nil)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "error getting user", err)
+			return
+		}
+		handler(w, r, user)
+	}
+}
+
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func respondWithJSON(w http.ResponseWriter, code int, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("error encoding response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// respondWithError logs err, which is not shown to the client, and sends
+// msg as a JSON error body.
+func respondWithError(w http.ResponseWriter, code int, msg string, err error) {
+	if err != nil {
+		log.Printf("%s: %v", msg, err)
+	}
+	respondWithJSON(w, code, map[string]string{"error": msg})
+}
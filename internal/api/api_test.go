@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"gator/internal/database"
+	"gator/internal/memory"
+	"gator/rss"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// respondWithError logs internal errors, which tests provoke on purpose
+	log.SetOutput(io.Discard)
+	m.Run()
+}
+
+type testServer struct {
+	t       *testing.T
+	db      *memory.Store
+	handler http.Handler
+}
+
+func newTestServer(t *testing.T) *testServer {
+	db := memory.New()
+	return &testServer{t: t, db: db, handler: New(db).Handler()}
+}
+
+// do sends a request as user, or anonymously when user is empty, and
+// returns the recorded response.
+func (s *testServer) do(method, path, user, body string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if user != "" {
+		req.Header.Set(UserHeader, user)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// expect sends a request and fails the test unless it gets status.
+func (s *testServer) expect(status int, method, path, user, body string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	rec := s.do(method, path, user, body)
+	if rec.Code != status {
+		s.t.Fatalf("%s %s: status %d, want %d; body %s", method, path, rec.Code, status, rec.Body)
+	}
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	err := json.Unmarshal(rec.Body.Bytes(), &v)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return v
+}
+
+func TestUsers(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": " alice "}`)
+	if user := decode[userResponse](t, rec); user.Name != "alice" {
+		t.Errorf("created user %q, want alice", user.Name)
+	}
+	s.expect(http.StatusConflict, "POST", "/v1/users", "", `{"name": "alice"}`)
+	s.expect(http.StatusBadRequest, "POST", "/v1/users", "", `{"name": `)
+	s.expect(http.StatusBadRequest, "POST", "/v1/users", "", `{"nickname": "bob"}`)
+	s.expect(http.StatusBadRequest, "POST", "/v1/users", "", `{"name": "  "}`)
+
+	users := decode[[]userResponse](t, s.expect(http.StatusOK, "GET", "/v1/users", "", ""))
+	if len(users) != 1 || users[0].Name != "alice" {
+		t.Errorf("users = %+v, want alice only", users)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": "alice"}`)
+
+	for _, route := range []struct{ method, path, body string }{
+		{"POST", "/v1/feeds", `{"name": "Blog", "url": "https://example.com/feed"}`},
+		{"GET", "/v1/follows", ""},
+		{"POST", "/v1/follows", `{"feed_url": "https://example.com/feed"}`},
+		{"DELETE", "/v1/follows?feed_url=https://example.com/feed", ""},
+		{"GET", "/v1/posts", ""},
+	} {
+		rec := s.do(route.method, route.path, "", route.body)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without %s: status %d, want 401", route.method, route.path, UserHeader, rec.Code)
+		}
+		rec = s.do(route.method, route.path, "mallory", route.body)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s as an unknown user: status %d, want 401", route.method, route.path, rec.Code)
+		}
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	s := newTestServer(t)
+	s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": "alice"}`)
+
+	rec := s.expect(http.StatusCreated, "POST", "/v1/feeds", "alice", `{"name": "Blog", "url": "https://example.com/feed"}`)
+	feed := decode[feedResponse](t, rec)
+	if feed != (feedResponse{Name: "Blog", URL: "https://example.com/feed", Owner: "alice"}) {
+		t.Errorf("created feed = %+v", feed)
+	}
+	s.expect(http.StatusConflict, "POST", "/v1/feeds", "alice", `{"name": "Again", "url": "https://example.com/feed"}`)
+	s.expect(http.StatusBadRequest, "POST", "/v1/feeds", "alice", `not json`)
+	s.expect(http.StatusBadRequest, "POST", "/v1/feeds", "alice", `{"name": "No url"}`)
+
+	feeds := decode[[]feedResponse](t, s.expect(http.StatusOK, "GET", "/v1/feeds", "", ""))
+	if len(feeds) != 1 || feeds[0] != feed {
+		t.Errorf("feeds = %+v, want %+v", feeds, feed)
+	}
+	// adding a feed follows it
+	follows := decode[[]followResponse](t, s.expect(http.StatusOK, "GET", "/v1/follows", "alice", ""))
+	if len(follows) != 1 || follows[0].FeedURL != "https://example.com/feed" {
+		t.Errorf("follows = %+v, want the new feed", follows)
+	}
+}
+
+// failingFollows is a store whose feed follows can't be created.
+type failingFollows struct {
+	*memory.Store
+}
+
+func (failingFollows) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	return database.CreateFeedFollowRow{}, errors.New("follows are broken")
+}
+
+func TestCreateFeedRemovesTheFeedWhenFollowingFails(t *testing.T) {
+	db := memory.New()
+	s := &testServer{t: t, db: db, handler: New(failingFollows{db}).Handler()}
+	s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": "alice"}`)
+
+	s.expect(http.StatusInternalServerError, "POST", "/v1/feeds", "alice", `{"name": "Blog", "url": "https://example.com/feed"}`)
+	feeds := decode[[]feedResponse](t, s.expect(http.StatusOK, "GET", "/v1/feeds", "", ""))
+	if len(feeds) != 0 {
+		t.Errorf("feeds = %+v, want the feed removed", feeds)
+	}
+}
+
+func TestFollows(t *testing.T) {
+	s := newTestServer(t)
+	s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": "alice"}`)
+	s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": "bob"}`)
+	s.expect(http.StatusCreated, "POST", "/v1/feeds", "alice", `{"name": "Blog", "url": "https://example.com/feed"}`)
+
+	rec := s.expect(http.StatusCreated, "POST", "/v1/follows", "bob", `{"feed_url": "https://example.com/feed"}`)
+	if follow := decode[followResponse](t, rec); follow.FeedName != "Blog" {
+		t.Errorf("followed %+v, want Blog", follow)
+	}
+	s.expect(http.StatusConflict, "POST", "/v1/follows", "bob", `{"feed_url": "https://example.com/feed"}`)
+	s.expect(http.StatusNotFound, "POST", "/v1/follows", "bob", `{"feed_url": "https://example.com/missing"}`)
+	s.expect(http.StatusBadRequest, "POST", "/v1/follows", "bob", `{"feed_url": ""}`)
+	s.expect(http.StatusBadRequest, "POST", "/v1/follows", "bob", `{"url": "https://example.com/feed"}`)
+
+	s.expect(http.StatusNoContent, "DELETE", "/v1/follows?feed_url=https://example.com/feed", "bob", "")
+	s.expect(http.StatusNotFound, "DELETE", "/v1/follows?feed_url=https://example.com/missing", "bob", "")
+	s.expect(http.StatusBadRequest, "DELETE", "/v1/follows", "bob", "")
+
+	follows := decode[[]followResponse](t, s.expect(http.StatusOK, "GET", "/v1/follows", "bob", ""))
+	if len(follows) != 0 {
+		t.Errorf("follows after unfollowing = %+v, want none", follows)
+	}
+}
+
+// seedPosts stores a feed followed by alice with one post that has full
+// content and one that has only a description.
+func seedPosts(t *testing.T, s *testServer) {
+	t.Helper()
+	ctx := context.Background()
+	s.expect(http.StatusCreated, "POST", "/v1/users", "", `{"name": "alice"}`)
+	s.expect(http.StatusCreated, "POST", "/v1/feeds", "alice", `{"name": "Blog", "url": "https://example.com/feed"}`)
+	feed, err := s.db.GetFeed(ctx, "https://example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, content := range []string{"<p>Full</p>", ""} {
+		_, err = s.db.UpsertPost(ctx, database.UpsertPostParams{
+			Title:       []string{"First", "Second"}[i],
+			Url:         []string{"https://example.com/1", "https://example.com/2"}[i],
+			Description: sql.NullString{String: "Summary", Valid: true},
+			PublishedAt: sql.NullTime{Time: time.Date(2026, 1, 2+i, 0, 0, 0, 0, time.UTC), Valid: true},
+			FeedID:      feed.ID,
+			Guid:        []string{"1", "2"}[i],
+			Content:     sql.NullString{String: content, Valid: content != ""},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPosts(t *testing.T) {
+	s := newTestServer(t)
+	seedPosts(t, s)
+
+	posts := decode[[]postResponse](t, s.expect(http.StatusOK, "GET", "/v1/posts", "alice", ""))
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2", len(posts))
+	}
+	if posts[0].Title != "Second" || posts[0].Content != nil {
+		t.Errorf("newest post = %+v, want Second without content", posts[0])
+	}
+	if posts[1].Content == nil || *posts[1].Content != "<p>Full</p>" {
+		t.Errorf("oldest post = %+v, want its full content", posts[1])
+	}
+
+	posts = decode[[]postResponse](t, s.expect(http.StatusOK, "GET", "/v1/posts?limit=1&unread=true", "alice", ""))
+	if len(posts) != 1 {
+		t.Errorf("got %d posts with limit=1, want 1", len(posts))
+	}
+	s.expect(http.StatusBadRequest, "GET", "/v1/posts?limit=0", "alice", "")
+	s.expect(http.StatusBadRequest, "GET", "/v1/posts?limit=many", "alice", "")
+	s.expect(http.StatusBadRequest, "GET", "/v1/posts?unread=maybe", "alice", "")
+}
+
+func TestUserFeed(t *testing.T) {
+	s := newTestServer(t)
+	seedPosts(t, s)
+
+	for _, test := range []struct{ path, contentType string }{
+		{"/v1/users/alice/feed.rss", "application/rss+xml; charset=utf-8"},
+		{"/v1/users/alice/feed.atom", "application/atom+xml; charset=utf-8"},
+	} {
+		rec := s.expect(http.StatusOK, "GET", test.path, "", "")
+		if got := rec.Header().Get("Content-Type"); got != test.contentType {
+			t.Errorf("%s: Content-Type %q, want %q", test.path, got, test.contentType)
+		}
+		feed, err := rss.ParseFeed(rec.Body.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", test.path, err)
+		}
+		if feed.Channel.Link == "" {
+			t.Errorf("%s: feed has no link", test.path)
+		}
+		if len(feed.Channel.Item) != 2 || feed.Channel.Item[0].Title != "Second" {
+			t.Errorf("%s: items = %+v, want Second then First", test.path, feed.Channel.Item)
+		}
+	}
+
+	s.expect(http.StatusNotFound, "GET", "/v1/users/bob/feed.rss", "", "")
+	s.expect(http.StatusBadRequest, "GET", "/v1/users/alice/feed.atom?limit=-1", "", "")
+	rec := s.expect(http.StatusOK, "GET", "/v1/users/alice/feed.rss?limit=1", "", "")
+	feed, err := rss.ParseFeed(rec.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Channel.Item) != 1 {
+		t.Errorf("got %d items with limit=1, want 1", len(feed.Channel.Item))
+	}
+}
+
+func TestUserFeedWriteError(t *testing.T) {
+	s := newTestServer(t)
+	seedPosts(t, s)
+	handler := New(s.db).handleUserFeed("application/rss+xml", func(w io.Writer, out *rss.Output) error {
+		io.WriteString(w, "<rss>")
+		return errors.New("disk full")
+	})
+
+	req := httptest.NewRequest("GET", "/v1/users/alice/feed.rss", nil)
+	req.SetPathValue("name", "alice")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := decode[map[string]string](t, rec); got["error"] != "error writing feed" {
+		t.Errorf("error = %v, want error writing feed", got)
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"gator/internal/database"
+	"net/http"
+	"strings"
+)
+
+type feedResponse struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Owner string `json:"owner"`
+}
+
+type followResponse struct {
+	FeedName    string `json:"feed_name"`
+	FeedURL     string `json:"feed_url"`
+	UnreadCount int64  `json:"unread_count"`
+}
+
+func (s *Server) handleListFeeds(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.DB.GetFeeds(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error listing feeds", err)
+		return
+	}
+	feeds := make([]feedResponse, 0, len(rows))
+	for _, row := range rows {
+		feeds = append(feeds, feedResponse{
+			Name:  row.Name,
+			URL:   row.Url,
+			Owner: row.UserName,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, feeds)
+}
+
+// handleCreateFeed adds a feed and follows it for the user, like the
+// addfeed command.
+func (s *Server) handleCreateFeed(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}{}
+	err := decodeJSON(r, &params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	params.URL = strings.TrimSpace(params.URL)
+	if params.Name == "" || params.URL == "" {
+		respondWithError(w, http.StatusBadRequest, "missing name or url", nil)
+		return
+	}
+
+	feed, err := s.DB.CreateFeed(r.Context(), database.CreateFeedParams{
+		Name:   params.Name,
+		Url:    params.URL,
+		UserID: user.ID,
+	})
+	if database.IsUniqueViolation(err) {
+		respondWithError(w, http.StatusConflict, "feed already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error creating feed", err)
+		return
+	}
+	_, err = s.DB.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err != nil {
+		// don't leave behind a feed nobody follows
+		err = errors.Join(err, s.DB.DeleteFeed(r.Context(), feed.ID))
+		respondWithError(w, http.StatusInternalServerError, "error following feed", err)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, feedResponse{
+		Name:  feed.Name,
+		URL:   feed.Url,
+		Owner: user.Name,
+	})
+}
+
+func (s *Server) handleListFollows(w http.ResponseWriter, r *http.Request, user database.User) {
+	rows, err := s.DB.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error listing follows", err)
+		return
+	}
+	follows := make([]followResponse, 0, len(rows))
+	for _, row := range rows {
+		follows = append(follows, followResponse{
+			FeedName:    row.FeedName,
+			FeedURL:     row.FeedUrl,
+			UnreadCount: row.UnreadCount,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, follows)
+}
+
+func (s *Server) handleFollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := struct {
+		FeedURL string `json:"feed_url"`
+	}{}
+	err := decodeJSON(r, &params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	feed, ok := s.lookupFeed(w, r, params.FeedURL)
+	if !ok {
+		return
+	}
+
+	follow, err := s.DB.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if database.IsUniqueViolation(err) {
+		respondWithError(w, http.StatusConflict, "already following feed", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error following feed", err)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, followResponse{
+		FeedName: follow.FeedName,
+		FeedURL:  feed.Url,
+	})
+}
+
+// handleUnfollow takes the feed from the feed_url query parameter, as
+// DELETE requests carry no body.
+func (s *Server) handleUnfollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	feed, ok := s.lookupFeed(w, r, r.URL.Query().Get("feed_url"))
+	if !ok {
+		return
+	}
+	err := s.DB.UnfollowFeed(r.Context(), database.UnfollowFeedParams{
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error unfollowing feed", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupFeed finds a feed by url, responding with the matching error
+// status when it cannot.
+func (s *Server) lookupFeed(w http.ResponseWriter, r *http.Request, url string) (database.Feed, bool) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		respondWithError(w, http.StatusBadRequest, "missing feed_url", nil)
+		return database.Feed{}, false
+	}
+	feed, err := s.DB.GetFeed(r.Context(), url)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "feed not found", nil)
+		return database.Feed{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error getting feed", err)
+		return database.Feed{}, false
+	}
+	return feed, true
+}
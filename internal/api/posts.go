@@ -0,0 +1,79 @@
+package api
+
+import (
+	"gator/internal/database"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPostLimit = 20
+	maxPostLimit     = 500
+)
+
+type postResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description string     `json:"description"`
+	Content     *string    `json:"content"`
+	PublishedAt *time.Time `json:"published_at"`
+	FeedName    string     `json:"feed_name"`
+	Read        bool       `json:"read"`
+}
+
+// handleListPosts returns the newest posts of the user's feeds. It takes
+// the optional query parameters limit and unread=true.
+func (s *Server) handleListPosts(w http.ResponseWriter, r *http.Request, user database.User) {
+	limit := defaultPostLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPostLimit {
+			respondWithError(w, http.StatusBadRequest, "invalid limit", nil)
+			return
+		}
+		limit = parsed
+	}
+	unreadOnly := false
+	if value := r.URL.Query().Get("unread"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid unread", nil)
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	rows, err := s.DB.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+		UserID:     user.ID,
+		UnreadOnly: unreadOnly,
+		Limit:      int32(limit),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error listing posts", err)
+		return
+	}
+
+	posts := make([]postResponse, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse{
+			ID:          row.ID,
+			Title:       row.Title,
+			URL:         row.Url,
+			Description: row.Description.String,
+			FeedName:    row.FeedName,
+			Read:        row.Read,
+		}
+		if row.PublishedAt.Valid {
+			post.PublishedAt = &row.PublishedAt.Time
+		}
+		if row.Content.Valid {
+			post.Content = &row.Content.String
+		}
+		posts = append(posts, post)
+	}
+	respondWithJSON(w, http.StatusOK, posts)
+}
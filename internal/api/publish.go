@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"gator/internal/publish"
+	"gator/rss"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const defaultFeedLimit = 50
+
+// handleUserFeed serves a user's timeline as a feed. It is addressed by
+// user name rather than UserHeader so feed readers can subscribe to it.
+func (s *Server) handleUserFeed(contentType string, write func(io.Writer, *rss.Output) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultFeedLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > maxPostLimit {
+				respondWithError(w, http.StatusBadRequest, "invalid limit", nil)
+				return
+			}
+			limit = parsed
+		}
+
+		user, err := s.DB.GetUser(r.Context(), r.PathValue("name"))
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user not found", nil)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "error getting user", err)
+			return
+		}
+
+		out, err := publish.UserFeed(r.Context(), s.DB, &user, int32(limit), requestURL(r))
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "error building feed", err)
+			return
+		}
+		// render the whole feed first, so a failure can still be reported
+		// as a JSON error instead of a truncated document
+		var body bytes.Buffer
+		err = write(&body, out)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "error writing feed", err)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body.Bytes())
+	}
+}
+
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
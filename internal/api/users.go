@@ -0,0 +1,51 @@
+package api
+
+import (
+	"gator/internal/database"
+	"net/http"
+	"strings"
+)
+
+type userResponse struct {
+	Name string `json:"name"`
+}
+
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	names, err := s.DB.GetUsers(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error listing users", err)
+		return
+	}
+	users := make([]userResponse, 0, len(names))
+	for _, name := range names {
+		users = append(users, userResponse{Name: name})
+	}
+	respondWithJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	params := struct {
+		Name string `json:"name"`
+	}{}
+	err := decodeJSON(r, &params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	if params.Name == "" {
+		respondWithError(w, http.StatusBadRequest, "missing name", nil)
+		return
+	}
+
+	user, err := s.DB.CreateUser(r.Context(), params.Name)
+	if database.IsUniqueViolation(err) {
+		respondWithError(w, http.StatusConflict, "user already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "error creating user", err)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, userResponse{Name: user.Name})
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/output"
+	"gator/rss"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	minFailureBackoff = time.Minute
+	maxFailureBackoff = 24 * time.Hour
+	// scrapeTimeout bounds a single fetch well under the claim lease, so a
+	// hung feed fails before another worker may claim it again.
+	scrapeTimeout = database.FeedClaimLease / 5
+)
+
+// ScrapeFeeds claims the due feed that was fetched least recently and
+// stores its posts. The claim marks the feed fetched and leases it until
+// the outcome is recorded, so concurrent scrapers never take the same feed.
+// The outcome replaces the lease; failures, including fetches that take
+// longer than scrapeTimeout, push its next fetch back.
+func ScrapeFeeds(ctx context.Context, state *State) error {
+	feed, err := state.DB.ClaimNextFeedToFetch(ctx, database.FeedClaimLeaseSeconds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil { return fmt.Errorf("error claiming feed: %v", err) }
+
+	scrapeCtx, cancel := context.WithTimeout(ctx, scrapeTimeout)
+	scrapeErr := scrapeFeed(scrapeCtx, state, &feed)
+	cancel()
+	if scrapeErr != nil && ctx.Err() != nil {
+		return scrapeErr
+	}
+	if scrapeErr != nil {
+		err = state.DB.RecordFeedFailure(
+			ctx,
+			database.RecordFeedFailureParams{
+				ID: feed.ID,
+				LastError: sql.NullString{
+					String: scrapeErr.Error(),
+					Valid: true,
+				},
+				NextFetchAt: sql.NullTime{
+					Time: time.Now().Add(failureBackoff(feed.ConsecutiveFailures + 1)),
+					Valid: true,
+				},
+			},
+		)
+		if err != nil { return fmt.Errorf("error recording failure of %s: %v", feed.Url, err) }
+		return fmt.Errorf("error scraping %s: %v", feed.Url, scrapeErr)
+	}
+
+	err = state.DB.RecordFeedSuccess(
+		ctx,
+		database.RecordFeedSuccessParams{
+			ID: feed.ID,
+			NextFetchAt: sql.NullTime{
+				Time: nextFetchTime(&feed, time.Now()),
+				Valid: true,
+			},
+		},
+	)
+	if err != nil { return fmt.Errorf("error recording success of %s: %v", feed.Url, err) }
+	return nil
+}
+
+
+// failureBackoff doubles the wait before the next attempt with every
+// consecutive failure, up to a day.
+func failureBackoff(failures int32) time.Duration {
+	backoff := minFailureBackoff
+	for i := int32(1); i < failures && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxFailureBackoff)
+}
+
+
+func scrapeFeed(ctx context.Context, state *State, feed *database.Feed) error {
+	fetchedFeed, cache, err := rss.FetchFeed(
+		ctx,
+		feed.Url,
+		rss.CacheValidators{
+			ETag: feed.Etag.String,
+			LastModified: feed.LastModified.String,
+		},
+	)
+	if errors.Is(err, rss.ErrNotModified) {
+		return nil
+	}
+	if err != nil { return err }
+
+	err = updateFeedSchedule(ctx, state, feed, fetchedFeed.Schedule())
+	if err != nil { return err }
+
+	siteURL := strings.TrimSpace(fetchedFeed.Channel.Link)
+	if siteURL != "" && siteURL != feed.SiteUrl.String {
+		err = state.DB.SetFeedSiteURL(
+			ctx,
+			database.SetFeedSiteURLParams{
+				ID: feed.ID,
+				SiteUrl: sql.NullString{
+					String: siteURL,
+					Valid: true,
+				},
+			},
+		)
+		if err != nil { return fmt.Errorf("error saving site url: %v", err) }
+	}
+
+	for _, item := range fetchedFeed.Channel.Item {
+		date, err := parseDateFormat(item.PubDate)
+		if err != nil { fmt.Printf("error parsing date %q of %s\n", item.PubDate, item.Link) }
+		guid := item.Identity()
+		// posts stored before guids were tracked are keyed by their link
+		// and take the guid over, rather than being stored twice
+		if guid != item.Link {
+			err = state.DB.AdoptLegacyPost(
+				ctx,
+				database.AdoptLegacyPostParams{
+					Guid: guid,
+					FeedID: feed.ID,
+					Url: item.Link,
+				},
+			)
+			if err != nil {
+				if ctx.Err() != nil { return ctx.Err() }
+				fmt.Printf("error saving post %s: %v\n", item.Link, err)
+				continue
+			}
+		}
+		_, err = state.DB.UpsertPost(
+			ctx,
+			database.UpsertPostParams{
+				Title: item.Title,
+				Url: item.Link,
+				Description: sql.NullString{
+					String: item.Description,
+					Valid: true,
+				},
+				PublishedAt: sql.NullTime{
+					Time: date,
+					Valid: !date.IsZero(),
+				},
+				FeedID: feed.ID,
+				Guid: guid,
+				Content: sql.NullString{
+					String: item.Content,
+					Valid: item.Content != "",
+				},
+			},
+		)
+		// no rows means the post is stored already and has not changed
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			if ctx.Err() != nil { return ctx.Err() }
+			fmt.Printf("error saving post %s: %v\n", item.Link, err)
+			continue
+		}
+		err = saveEnclosures(ctx, state, feed, &item)
+		if err != nil {
+			if ctx.Err() != nil { return ctx.Err() }
+			fmt.Printf("error saving enclosures of %s: %v\n", item.Link, err)
+		}
+	}
+
+	err = updateAdaptiveInterval(ctx, state, feed)
+	if err != nil { return err }
+
+	err = state.DB.UpdateFeedCache(
+		ctx,
+		database.UpdateFeedCacheParams{
+			ID: feed.ID,
+			Etag: sql.NullString{
+				String: cache.ETag,
+				Valid: cache.ETag != "",
+			},
+			LastModified: sql.NullString{
+				String: cache.LastModified,
+				Valid: cache.LastModified != "",
+			},
+		},
+	)
+	if err != nil { return fmt.Errorf("error saving feed cache validators: %v", err) }
+
+	return nil
+}
+
+
+// saveEnclosures stores the item's media. It runs for unchanged posts too,
+// so posts saved before their enclosures were tracked get them.
+func saveEnclosures(ctx context.Context, state *State, feed *database.Feed, item *rss.RSSItem) error {
+	episode := item.Episode()
+	image := item.Image()
+	for _, enclosure := range item.Media() {
+		err := state.DB.UpsertEnclosure(
+			ctx,
+			database.UpsertEnclosureParams{
+				Url: enclosure.URL,
+				MimeType: sql.NullString{
+					String: enclosure.Type,
+					Valid: enclosure.Type != "",
+				},
+				Length: sql.NullInt64{
+					Int64: enclosure.Length,
+					Valid: enclosure.Length > 0,
+				},
+				DurationSeconds: sql.NullInt32{
+					Int32: int32(enclosure.Duration / time.Second),
+					Valid: enclosure.Duration >= time.Second,
+				},
+				Episode: sql.NullInt32{
+					Int32: int32(episode),
+					Valid: episode > 0,
+				},
+				ImageUrl: sql.NullString{
+					String: image,
+					Valid: image != "",
+				},
+				FeedID: feed.ID,
+				Guid: item.Identity(),
+			},
+		)
+		if err != nil { return err }
+	}
+	return nil
+}
+
+
+// HandlerAgg runs one worker per unit of concurrency. Each worker scrapes
+// a feed on every tick, so a slow feed only holds up its own worker. A
+// failing feed is reported and skipped. SIGINT or SIGTERM cancels the
+// fetches in flight and returns once every worker has stopped.
+func HandlerAgg(state *State, cmd Command) error {
+	timeBetweenReqs, err := time.ParseDuration(cmd.Arguments[0])
+	if err != nil { return fmt.Errorf("error parsing duration: %v", err) }
+	workers := 1
+	if len(cmd.Arguments) > 1 {
+		workers, err = strconv.Atoi(cmd.Arguments[1])
+		if err != nil || workers < 1 {
+			return fmt.Errorf("invalid concurrency: %s", cmd.Arguments[1])
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Fetching feeds every %s with %d workers\n", timeBetweenReqs, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			aggWorker(ctx, state, timeBetweenReqs)
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("Aggregator stopped\n")
+	return nil
+}
+
+
+func aggWorker(ctx context.Context, state *State, timeBetweenReqs time.Duration) {
+	ticker := time.NewTicker(timeBetweenReqs)
+	defer ticker.Stop()
+	for {
+		err := ScrapeFeeds(ctx, state)
+		if err != nil && ctx.Err() == nil {
+			fmt.Printf("%v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+
+func HandlerFeedHealth(state *State, cmd Command) error {
+	feeds, err := state.DB.GetFailingFeeds(context.Background())
+	if err != nil { return fmt.Errorf("error listing failing feeds: %v", err) }
+	return render(cmd, "All feeds are healthy", []output.Column[database.Feed]{
+		{Name: "name", Value: func(feed database.Feed) any { return feed.Name }},
+		{Name: "url", Value: func(feed database.Feed) any { return feed.Url }},
+		{Name: "failures", Value: func(feed database.Feed) any { return feed.ConsecutiveFailures }},
+		{Name: "last_success_at", Value: func(feed database.Feed) any { return output.NullTime(feed.LastSuccessAt) }},
+		{Name: "next_fetch_at", Value: func(feed database.Feed) any { return output.NullTime(feed.NextFetchAt) }},
+		{Name: "last_error", Value: func(feed database.Feed) any { return output.NullString(feed.LastError) }},
+	}, feeds)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const guidFeed = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+	<title>Example</title>
+	<link>https://example.com/</link>
+	<item>
+		<title>First post</title>
+		<link>https://example.com/first?utm_source=rss</link>
+		<guid isPermaLink="false">tag:example.com,2026:1</guid>
+		<pubDate>Fri, 02 Jan 2026 10:00:00 +0000</pubDate>
+		<description>Hello</description>
+	</item>
+</channel>
+</rss>`
+
+func TestScrapeFeedAdoptsPostsStoredBeforeGuids(t *testing.T) {
+	ctx := context.Background()
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	server := serveFeed(t, guidFeed)
+	feed := addFeed(t, state, user, "Example", server.URL)
+
+	// before guids were tracked, the migration keyed every post by its url
+	legacy, err := state.DB.UpsertPost(ctx, database.UpsertPostParams{
+		Title: "First post",
+		Url: "https://example.com/first?utm_source=rss",
+		Description: sql.NullString{String: "Hello", Valid: true},
+		FeedID: feed.ID,
+		Guid: "https://example.com/first?utm_source=rss",
+	})
+	if err != nil { t.Fatalf("UpsertPost: %v", err) }
+	err = state.DB.MarkPostRead(ctx, database.MarkPostReadParams{UserID: user.ID, PostID: legacy.ID})
+	if err != nil { t.Fatalf("MarkPostRead: %v", err) }
+
+	for range 2 {
+		err = scrapeFeed(ctx, state, &feed)
+		if err != nil { t.Fatalf("scrapeFeed: %v", err) }
+	}
+
+	posts, err := state.DB.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil { t.Fatalf("GetPostsForUser: %v", err) }
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want the stored post only", len(posts))
+	}
+	if posts[0].ID != legacy.ID {
+		t.Errorf("post id = %v, want the stored post %v", posts[0].ID, legacy.ID)
+	}
+	if posts[0].Guid != "tag:example.com,2026:1" {
+		t.Errorf("guid = %q, want the item's guid", posts[0].Guid)
+	}
+	if !posts[0].Read {
+		t.Error("the stored post lost its read state")
+	}
+}
+
+func TestScrapeFeedStoresItemsOnce(t *testing.T) {
+	ctx := context.Background()
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	server := serveFeed(t, guidFeed)
+	feed := addFeed(t, state, user, "Example", server.URL)
+
+	for range 3 {
+		err := scrapeFeed(ctx, state, &feed)
+		if err != nil { t.Fatalf("scrapeFeed: %v", err) }
+	}
+	posts, err := state.DB.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil { t.Fatalf("GetPostsForUser: %v", err) }
+	if len(posts) != 1 {
+		t.Fatalf("got %d posts, want 1", len(posts))
+	}
+}
+
+func TestAgg(t *testing.T) {
+	ctx := context.Background()
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	healthy := addFeed(t, state, user, "Example", serveFeed(t, guidFeed).URL)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(broken.Close)
+	failing := addFeed(t, state, user, "Broken", broken.URL)
+
+	interruptWhen(t, func() bool {
+		a, err := state.DB.GetFeed(ctx, healthy.Url)
+		if err != nil { return false }
+		b, err := state.DB.GetFeed(ctx, failing.Url)
+		if err != nil { return false }
+		return a.LastSuccessAt.Valid && b.ConsecutiveFailures > 0
+	})
+	out := mustRun(t, state, "agg", "10ms", "2")
+	assertContains(t, out, "Fetching feeds every 10ms with 2 workers", "error scraping "+broken.URL, "Aggregator stopped")
+
+	posts, err := state.DB.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil { t.Fatalf("GetPostsForUser: %v", err) }
+	if len(posts) != 1 || posts[0].Title != "First post" {
+		t.Errorf("posts = %+v, want the item of the healthy feed", posts)
+	}
+
+	// a failing feed backs off instead of being fetched on every tick
+	feed, err := state.DB.GetFeed(ctx, failing.Url)
+	if err != nil { t.Fatal(err) }
+	if wait := time.Until(feed.NextFetchAt.Time); wait < 50*time.Second {
+		t.Errorf("failing feed is fetched again in %v, want about %v", wait, minFailureBackoff)
+	}
+	if !feed.LastError.Valid {
+		t.Error("failing feed has no last error")
+	}
+
+	runError(t, state, "error parsing duration", "agg", "often")
+	runError(t, state, "invalid concurrency: 0", "agg", "1m", "0")
+	runError(t, state, "expected at least 1 argument", "agg")
+}
+
+func TestAggKeepsFetchingPastAHungFeed(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(hung.Close)
+	addFeed(t, state, user, "Hung", hung.URL)
+	var fetches atomic.Int32
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write([]byte(guidFeed))
+	}))
+	t.Cleanup(healthy.Close)
+	addFeed(t, state, user, "Example", healthy.URL)
+
+	// with a barrier per batch the healthy feed would be fetched only once
+	interruptWhen(t, func() bool { return fetches.Load() >= 3 })
+	out := mustRun(t, state, "agg", "10ms", "2")
+	assertContains(t, out, "Aggregator stopped")
+}
+
+func TestScrapeFeedClampsScheduleHints(t *testing.T) {
+	ctx := context.Background()
+	state := newTestState(t)
+	state.Config.PollMaxInterval = "6h"
+	user := loginAs(t, state, "alice")
+	server := serveFeed(t, `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+	<title>Example</title>
+	<link>https://example.com/</link>
+	<ttl>9999999999</ttl>
+</channel>
+</rss>`)
+	feed := addFeed(t, state, user, "Example", server.URL)
+
+	err := scrapeFeed(ctx, state, &feed)
+	if err != nil { t.Fatalf("scrapeFeed: %v", err) }
+	stored, err := state.DB.GetFeed(ctx, server.URL)
+	if err != nil { t.Fatal(err) }
+	if hint := stored.HintedIntervalSeconds; !hint.Valid || hint.Int32 != 6*60*60 {
+		t.Errorf("hinted interval = %+v seconds, want poll_max_interval", hint)
+	}
+}
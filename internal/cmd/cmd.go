@@ -3,80 +3,52 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
+	"flag"
 	"fmt"
+	"math"
 	"strings"
 	"strconv"
 	"gator/internal/config"
 	"gator/internal/database"
-	"gator/rss"
-	"os"
+	"gator/internal/migrate"
+	"gator/internal/output"
 	"time"
 	"github.com/google/uuid"
 )
 
 type State struct {
 	Config *config.Config
-	DB *database.Queries
+	DB database.Querier
+	// Migrator is nil for stores without a schema, such as memory.Store.
+	Migrator *migrate.Migrator
 }
 
-type Commands struct {
-	Commands map[string]func(*State, Command) error
-}
-
-func (c *Commands) Run(s *State, cmd Command) error {
-	function, ok := c.Commands[cmd.Name]
-	if !ok { return fmt.Errorf("unknown command: %s", cmd.Name) }
-	return function(s, cmd)
-}
-
-func (c *Commands) Register(name string, function func(*State, Command) error) {
-	c.Commands[name] = function
-}
-
-
+// Command is one invocation. Flags holds the flags parsed against the
+// command's Spec; Arguments are the positional arguments left over.
 type Command struct {
 	Name string
 	Arguments []string
+	Flags *flag.FlagSet
 }
 
 func HandlerLogin(state *State, cmd Command) error {
-	if len(cmd.Arguments) < 1 {
-		return fmt.Errorf("missing username")
-	}
 	_, err := state.DB.GetUser(context.Background(), cmd.Arguments[0])
-	if err != nil { 
-		fmt.Printf("error getting user: %v", err) 
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error getting user: %v", err) }
 
-		err = state.Config.SetUser(cmd.Arguments[0])
-	if err != nil { 
-		fmt.Printf("error setting user: %v", err) 
-	os.Exit(1)
-	}
+	err = state.Config.SetUser(cmd.Arguments[0])
+	if err != nil { return fmt.Errorf("error setting user: %v", err) }
 	fmt.Printf("User set to %s\n", cmd.Arguments[0])
 	return nil
 }
 
 func HandlerRegister(state *State, cmd Command) error {
-	if len(cmd.Arguments) < 1 {
-		return fmt.Errorf("missing username")
-	}
-
 	user, err := state.DB.CreateUser(
 		context.Background(), 	
 		cmd.Arguments[0],
 	)
-	if err != nil { 
-		fmt.Printf("error creating user: %v", err) 
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error creating user: %v", err) }
 	err = state.Config.SetUser(user.Name)
-	if err != nil { 
-		fmt.Printf("error setting user: %v", err) 
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error setting user: %v", err) }
 	fmt.Printf("User created: %s\n", user)
 	return nil
 }
@@ -84,10 +56,7 @@ func HandlerRegister(state *State, cmd Command) error {
 
 func HandlerReset(state *State, cmd Command) error {
 	err := state.DB.TruncateUsers(context.Background())
-	if err != nil { 
-		fmt.Printf("error truncating users: %v", err) 
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error truncating users: %v", err) }
 	fmt.Printf("Users truncated\n")
 	return nil
 }
@@ -95,98 +64,16 @@ func HandlerReset(state *State, cmd Command) error {
 
 func HandlerListUsers(state *State, cmd Command) error {
 	users, err := state.DB.GetUsers(context.Background())
-	if err != nil { 
-		fmt.Printf("error listing users: %v", err)
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error listing users: %v", err) }
 	currentUser := state.Config.CurrentUser
-	for _, user := range users {
-		if user == currentUser {
-			fmt.Printf("* %s (current)\n", user)
-		} else {
-			fmt.Printf("* %s\n", user)
-		}
-	}
-	return nil
-}
-
-
-func ScrapeFeeds(state *State) error {
-	feed, err := state.DB.GetNextFeedToFetch(context.Background())
-	if err != nil { 
-		fmt.Printf("error fetching feed: %v", err)
-		os.Exit(1)
-	}
-	err = state.DB.MarkFeedFetched(context.Background(), feed.ID)
-	if err != nil { 
-		fmt.Printf("error marking feed fetched: %v", err)
-		os.Exit(1)
-	}
-
-	fetchedFeed, err := rss.FetchFeed(context.Background(), feed.Url)
-	if err != nil { 
-		fmt.Printf("error fetching feed: %v", err)
-		os.Exit(1)
-	}
-	for _, item := range fetchedFeed.Channel.Item {
-		date, err := parseDateFormat(item.PubDate)
-		if err != nil { fmt.Println("error parsing date", err) }
-		state.DB.CreatePost(
-			context.Background(),
-			database.CreatePostParams{
-				Title:item.Title,
-				Url: item.Link,
-				Description: sql.NullString{
-					String: item.Description,
-					Valid:true,
-				},
-				PublishedAt: sql.NullTime{
-					Time: date,
-					Valid: true,
-				},
-				FeedID: feed.ID,
-			},
-		)
-	}
-
-	return nil
-}
-
-
-func HandlerAgg(state *State, cmd Command) error {
-	if len(cmd.Arguments) < 1 {
-		return fmt.Errorf("missing feed url")
-	}
-	timeBetweenReqs, err := time.ParseDuration(cmd.Arguments[0])
-	if err != nil { 
-		fmt.Printf("error parsing duration: %v", err)
-		os.Exit(1)
-	}
-	fmt.Printf("Fetching feeds every %s\n", timeBetweenReqs)
-	
-	ticker := time.NewTicker(timeBetweenReqs)
-	for ; ; <-ticker.C {
-		err := ScrapeFeeds(state)
-		if err != nil { 
-			fmt.Printf("error fetching feed: %v", err)
-			os.Exit(1)
-		}
-	}
-
-	if err != nil { 
-		fmt.Printf("error fetching feed: %v", err)
-		os.Exit(1)
-	}
-	fmt.Printf("Feed fetched\n")
-	return nil
+	return render(cmd, "No users", []output.Column[string]{
+		{Name: "name", Value: func(user string) any { return user }},
+		{Name: "current", Value: func(user string) any { return user == currentUser }},
+	}, users)
 }
 
 
 func HandlerAddFeed(state *State, cmd Command, user *database.User) error {
-	if len(cmd.Arguments) < 2 {
-		return fmt.Errorf("missing feed url oR name")
-		os.Exit(1)
-	}
 	feedName := cmd.Arguments[0]
 	feedURL := cmd.Arguments[1]
 	
@@ -198,36 +85,33 @@ func HandlerAddFeed(state *State, cmd Command, user *database.User) error {
 			UserID: user.ID,
 		},
 	)
-	if err != nil {
-		fmt.Printf("error creating feed: %v", err)
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error creating feed: %v", err) }
 	fmt.Printf("Feed created: %s\n", feedName)
 	
-	feed := state.GetFeedByURL(feedURL)
-	_ = state.CreateFeedFollow(user.ID, feed.ID)
-
-	return nil
+	feed, err := state.GetFeedByURL(feedURL)
+	if err != nil { return err }
+	_, err = state.CreateFeedFollow(user.ID, feed.ID)
+	return err
 }	
 
 
 func HandlerListFeeds(state *State, cmd Command) error {
 	feeds, err := state.DB.GetFeeds(context.Background())
-	if err != nil { 
-		fmt.Printf("error listing feeds: %v", err)
-		os.Exit(1)
-	}
-	for _, feed := range feeds {
-		fmt.Printf("* %s %s %s\n", feed.Name, feed.Url, feed.UserName)
-	}
-	return nil
+	if err != nil { return fmt.Errorf("error listing feeds: %v", err) }
+	return render(cmd, "No feeds", []output.Column[database.GetFeedsRow]{
+		{Name: "name", Value: func(feed database.GetFeedsRow) any { return feed.Name }},
+		{Name: "url", Value: func(feed database.GetFeedsRow) any { return feed.Url }},
+		{Name: "user", Value: func(feed database.GetFeedsRow) any { return feed.UserName }},
+	}, feeds)
 }
 
 func HandlerFollow(state *State, cmd Command, user *database.User) error {
 	
-	feed := state.GetFeedByURL(cmd.Arguments[0])
+	feed, err := state.GetFeedByURL(cmd.Arguments[0])
+	if err != nil { return err }
 	
-	follows := state.CreateFeedFollow(user.ID, feed.ID)
+	follows, err := state.CreateFeedFollow(user.ID, feed.ID)
+	if err != nil { return err }
 	fmt.Println(follows)
 	return nil
 }
@@ -237,70 +121,56 @@ func HandlerListUserFollows(state *State, cmd Command, user *database.User) erro
 		context.Background(),
 		user.ID,
 	)
-	if err != nil { 
-		fmt.Printf("error listing user follows: %v", err)
-		os.Exit(1)
-	}
-	for _, follow := range follows {
-		fmt.Printf("* %s %s\n", follow.FeedName, follow.UserName)
-	}
-	return nil
+	if err != nil { return fmt.Errorf("error listing user follows: %v", err) }
+	return render(cmd, "You do not follow any feeds", []output.Column[database.GetFeedFollowsForUserRow]{
+		{Name: "feed", Value: func(follow database.GetFeedFollowsForUserRow) any { return follow.FeedName }},
+		{Name: "url", Value: func(follow database.GetFeedFollowsForUserRow) any { return follow.FeedUrl }},
+		{Name: "unread", Value: func(follow database.GetFeedFollowsForUserRow) any { return follow.UnreadCount }},
+	}, follows)
 }
 
 
 func HandlerUnfollow(state *State, cmd Command, user *database.User) error {
 	feedURL := cmd.Arguments[0]
-	feed := state.GetFeedByURL(feedURL)
+	feed, err := state.GetFeedByURL(feedURL)
+	if err != nil { return err }
 
-	err := state.DB.UnfollowFeed(
+	err = state.DB.UnfollowFeed(
 		context.Background(),
 		database.UnfollowFeedParams{
 			UserID: user.ID,
 			FeedID: feed.ID,
 		},
 	)
-	if err != nil { 
-		fmt.Printf("error unfollowing feed: %v", err)
-		os.Exit(1)
-	}
+	if err != nil { return fmt.Errorf("error unfollowing feed: %v", err) }
 	return nil
 }
 
 
 func HandlerBrowse(state *State, cmd Command, user *database.User) error {
-	var limit *int32
-	if len(cmd.Arguments) < 1 {
-		fmt.Println("missing limit")
-		var defaultLimit int32 = 2
-		limit = &defaultLimit
-	} else {
-		fmt.Printf("parsing limit: %s\n", cmd.Arguments[0])
+	var limit int32 = 2
+	if len(cmd.Arguments) > 0 {
 		num, err := strconv.Atoi(cmd.Arguments[0])
-		num32 := int32(num)
-		limit = &num32	
-		if err != nil { 
-			fmt.Printf("error parsing limit: %v", err)
-			os.Exit(1)
-		}
-		
+		if err != nil || num < 1 { return fmt.Errorf("invalid limit: %s", cmd.Arguments[0]) }
+		limit = int32(num)
 	}
-	fmt.Printf("Limit: %d\n", *limit)
 	posts, err := state.DB.GetPostsForUser(
 		context.Background(),
 		database.GetPostsForUserParams{
 			UserID: user.ID,
-			Limit: *limit,
+			UnreadOnly: cmd.Bool("unread"),
+			Limit: limit,
 		},
 	)
-	fmt.Printf("Posts: %v\n", posts)
-	if err != nil { 
-		fmt.Printf("error listing posts: %v", err)
-		os.Exit(1)
-	}
-	for _, post := range posts {
-		fmt.Printf("* %s %s %s\n", post.Title, post.FeedName, post.PublishedAt)
-	}
-	return nil	
+	if err != nil { return fmt.Errorf("error listing posts: %v", err) }
+	return render(cmd, "No posts", []output.Column[database.GetPostsForUserRow]{
+		{Name: "id", Value: func(post database.GetPostsForUserRow) any { return post.ID }},
+		{Name: "read", Value: func(post database.GetPostsForUserRow) any { return post.Read }},
+		{Name: "title", Value: func(post database.GetPostsForUserRow) any { return post.Title }},
+		{Name: "feed", Value: func(post database.GetPostsForUserRow) any { return post.FeedName }},
+		{Name: "published_at", Value: func(post database.GetPostsForUserRow) any { return output.NullTime(post.PublishedAt) }},
+		{Name: "url", Value: func(post database.GetPostsForUserRow) any { return post.Url }},
+	}, posts)
 }
 	
 
@@ -309,32 +179,27 @@ func HandlerBrowse(state *State, cmd Command, user *database.User) error {
 
 func MiddlewareLoggedIn(handler func(state *State, cmd Command, user *database.User) error) func(*State, Command) error {
 	return func(state *State, cmd Command) error {
-		currentUser := state.GetCurrentUser()
+		currentUser, err := state.GetCurrentUser()
+		if err != nil { return err }
 		return handler(state, cmd, currentUser)
 	}
 }
 
 
-func (state *State) GetCurrentUser() *database.User {
+func (state *State) GetCurrentUser() (*database.User, error) {
 	currentUser, err := state.DB.GetUser(
 		context.Background(), state.Config.CurrentUser)
-	if err != nil {
-		fmt.Printf("error getting current user: %v", err)
-		os.Exit(1)
-	}	
-	return &currentUser
+	if err != nil { return nil, fmt.Errorf("error getting current user: %v", err) }
+	return &currentUser, nil
 }
 
-func (state *State) GetFeedByURL(url string) *database.Feed {
+func (state *State) GetFeedByURL(url string) (*database.Feed, error) {
 	feed, err := state.DB.GetFeed(context.Background(), url)
-	if err != nil { 
-		fmt.Printf("error getting feed: %v", err)
-		os.Exit(1)
-	}
-	return &feed
+	if err != nil { return nil, fmt.Errorf("error getting feed: %v", err) }
+	return &feed, nil
 }
 
-func (state *State) CreateFeedFollow(userID uuid.UUID, feedID uuid.UUID) *database.CreateFeedFollowRow {
+func (state *State) CreateFeedFollow(userID uuid.UUID, feedID uuid.UUID) (*database.CreateFeedFollowRow, error) {
 	follows, err := state.DB.CreateFeedFollow(
 		context.Background(), 
 		database.CreateFeedFollowParams{
@@ -342,11 +207,16 @@ func (state *State) CreateFeedFollow(userID uuid.UUID, feedID uuid.UUID) *databa
 			FeedID: feedID,
 		},
 	)
-	if err != nil {
-		fmt.Printf("error creating feed follow: %v", err)
-		os.Exit(1)
-	}
-	return &follows
+	if err != nil { return nil, fmt.Errorf("error creating feed follow: %v", err) }
+	return &follows, nil
+}
+
+
+// limitFlag returns --limit, which must be a positive int32.
+func limitFlag(cmd Command) (int32, error) {
+	limit := cmd.Int("limit")
+	if limit < 1 || limit > math.MaxInt32 { return 0, fmt.Errorf("invalid limit: %d", limit) }
+	return int32(limit), nil
 }
 
 
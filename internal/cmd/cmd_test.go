@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"database/sql"
+	"gator/internal/config"
+	"gator/internal/database"
+	"gator/internal/memory"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsers(t *testing.T) {
+	state := newTestState(t)
+
+	out := mustRun(t, state, "register", "alice")
+	assertContains(t, out, "User created: ")
+	if state.Config.CurrentUser != "alice" {
+		t.Errorf("current user = %q after registering, want alice", state.Config.CurrentUser)
+	}
+	runError(t, state, "error creating user", "register", "alice")
+	mustRun(t, state, "register", "bob")
+
+	out = mustRun(t, state, "login", "alice")
+	assertContains(t, out, "User set to alice")
+	saved, err := config.Read()
+	if err != nil { t.Fatalf("config.Read: %v", err) }
+	if saved.CurrentUser != "alice" {
+		t.Errorf("saved current user = %q, want alice", saved.CurrentUser)
+	}
+	runError(t, state, "error getting user", "login", "mallory")
+
+	out = mustRun(t, state, "--output", "csv", "users")
+	assertContains(t, out, "alice,true", "bob,false")
+
+	out = mustRun(t, state, "reset")
+	assertContains(t, out, "Users truncated")
+	out = mustRun(t, state, "users")
+	assertContains(t, out, "No users")
+}
+
+func TestLoggedInCommandsNeedAUser(t *testing.T) {
+	state := newTestState(t)
+	for _, args := range [][]string{
+		{"addfeed", "Blog", "https://example.com/feed"},
+		{"follow", "https://example.com/feed"},
+		{"following"},
+		{"unfollow", "https://example.com/feed"},
+		{"browse"},
+		{"export"},
+		{"starred"},
+		{"search", "go"},
+	} {
+		runError(t, state, "error getting current user", args...)
+	}
+}
+
+func TestFeedsAndFollows(t *testing.T) {
+	state := newTestState(t)
+	loginAs(t, state, "alice")
+
+	out := mustRun(t, state, "feeds")
+	assertContains(t, out, "No feeds")
+	out = mustRun(t, state, "addfeed", "Blog", "https://example.com/feed")
+	assertContains(t, out, "Feed created: Blog")
+	runError(t, state, "error creating feed", "addfeed", "Again", "https://example.com/feed")
+
+	out = mustRun(t, state, "--output", "csv", "feeds")
+	assertContains(t, out, "Blog,https://example.com/feed,alice")
+	out = mustRun(t, state, "--output", "csv", "following")
+	assertContains(t, out, "Blog,https://example.com/feed,0")
+
+	loginAs(t, state, "bob")
+	out = mustRun(t, state, "following")
+	assertContains(t, out, "You do not follow any feeds")
+	mustRun(t, state, "follow", "https://example.com/feed")
+	runError(t, state, "error creating feed follow", "follow", "https://example.com/feed")
+	runError(t, state, "error getting feed", "follow", "https://example.com/missing")
+
+	mustRun(t, state, "unfollow", "https://example.com/feed")
+	out = mustRun(t, state, "following")
+	assertContains(t, out, "You do not follow any feeds")
+	runError(t, state, "error getting feed", "unfollow", "https://example.com/missing")
+}
+
+func TestBrowse(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+
+	out := mustRun(t, state, "browse")
+	assertContains(t, out, "No posts")
+
+	feed := addFeed(t, state, user, "Blog", "https://example.com/feed")
+	for i, title := range []string{"First", "Second", "Third"} {
+		addPost(t, state, feed, database.UpsertPostParams{
+			Title: title,
+			Url: "https://example.com/" + title,
+			PublishedAt: sql.NullTime{Time: time.Date(2026, 1, 1+i, 0, 0, 0, 0, time.UTC), Valid: true},
+		})
+	}
+
+	out = mustRun(t, state, "--output", "jsonl", "browse")
+	assertContains(t, out, `"title":"Third"`, `"title":"Second"`)
+	if strings.Contains(out, "First") {
+		t.Errorf("browse shows more than the default two posts:\n%s", out)
+	}
+	out = mustRun(t, state, "browse", "--unread", "3")
+	assertContains(t, out, "First")
+
+	runError(t, state, "invalid limit", "browse", "0")
+	runError(t, state, "invalid limit", "browse", "many")
+	runError(t, state, "expected at most 1 argument", "browse", "1", "2")
+}
+
+func TestBrowseDatesUndatedPostsWhenStored(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	feed := addFeed(t, state, user, "Blog", "https://example.com/feed")
+
+	state.DB.(*memory.Store).SetClock(func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) })
+	addPost(t, state, feed, database.UpsertPostParams{Title: "Undated", Url: "https://example.com/undated"})
+	addPost(t, state, feed, database.UpsertPostParams{
+		Title: "Dated",
+		Url: "https://example.com/dated",
+		PublishedAt: sql.NullTime{Time: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+	})
+
+	out := mustRun(t, state, "--output", "csv", "browse", "1")
+	assertContains(t, out, "Dated")
+	if strings.Contains(out, "Undated") {
+		t.Errorf("an undated post stored before a dated one is listed first:\n%s", out)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	state := newTestState(t)
+
+	runError(t, state, "unknown command: fly", "fly")
+	runError(t, state, "register: expected at least 1 argument, got 0\nusage: gator register <name>", "register")
+	runError(t, state, "flag provided but not defined: -fast", "browse", "--fast")
+	runError(t, state, "output", "--output", "yaml", "users")
+
+	out := mustRun(t, state, "help")
+	assertContains(t, out, "Usage: gator <command>", "register", "serve")
+	out = mustRun(t, state, "help", "browse")
+	assertContains(t, out, "Usage: gator browse [--unread] [limit]", "-unread")
+	runError(t, state, "unknown command: fly", "help", "fly")
+
+	// --help needs neither a user nor valid arguments
+	out = mustRun(t, state, "addfeed", "--help")
+	assertContains(t, out, "Usage: gator addfeed <name> <url>")
+}
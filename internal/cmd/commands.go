@@ -0,0 +1,232 @@
+package cmd
+
+import "flag"
+
+// NewCommands returns the registry of every gator command, in the order
+// help lists them.
+func NewCommands() *Commands {
+	commands := &Commands{}
+	for _, spec := range []Spec{
+		{
+			Name: "register",
+			Usage: "<name>",
+			Description: "Create a user and log in as it",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: HandlerRegister,
+		},
+		{
+			Name: "login",
+			Usage: "<name>",
+			Description: "Log in as an existing user",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: HandlerLogin,
+		},
+		{
+			Name: "users",
+			Description: "List all users",
+			MaxArgs: 0,
+			Handler: HandlerListUsers,
+		},
+		{
+			Name: "reset",
+			Description: "Delete all users and their data",
+			MaxArgs: 0,
+			Handler: HandlerReset,
+		},
+		{
+			Name: "migrate",
+			Usage: "<up|down|status>",
+			Description: "Apply, roll back one, or list the schema migrations",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: HandlerMigrate,
+		},
+		{
+			Name: "addfeed",
+			Usage: "<name> <url>",
+			Description: "Add a feed and follow it",
+			MinArgs: 2, MaxArgs: 2,
+			Handler: MiddlewareLoggedIn(HandlerAddFeed),
+		},
+		{
+			Name: "feeds",
+			Description: "List all feeds",
+			MaxArgs: 0,
+			Handler: HandlerListFeeds,
+		},
+		{
+			Name: "follow",
+			Usage: "<url>",
+			Description: "Follow an existing feed",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerFollow),
+		},
+		{
+			Name: "following",
+			Description: "List the feeds you follow",
+			MaxArgs: 0,
+			Handler: MiddlewareLoggedIn(HandlerListUserFollows),
+		},
+		{
+			Name: "unfollow",
+			Usage: "<url>",
+			Description: "Unfollow a feed",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerUnfollow),
+		},
+		{
+			Name: "import",
+			Usage: "<file.opml>",
+			Description: "Follow every feed in an OPML file",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerImport),
+		},
+		{
+			Name: "export",
+			Usage: "[file.opml]",
+			Description: "Write the feeds you follow as OPML, to stdout by default",
+			MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerExport),
+		},
+		{
+			Name: "agg",
+			Usage: "<interval> [concurrency]",
+			Description: "Fetch due feeds every interval until interrupted",
+			MinArgs: 1, MaxArgs: 2,
+			Handler: HandlerAgg,
+		},
+		{
+			Name: "feedhealth",
+			Description: "List feeds whose last fetches failed",
+			MaxArgs: 0,
+			Handler: HandlerFeedHealth,
+		},
+		{
+			Name: "feedinfo",
+			Usage: "<url>",
+			Description: "Show the fetch schedule and health of a feed",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: HandlerFeedInfo,
+		},
+		{
+			Name: "setinterval",
+			Usage: "<url> <duration|auto>",
+			Description: "Override how often a feed is polled, or go back to automatic",
+			MinArgs: 2, MaxArgs: 2,
+			Handler: HandlerSetInterval,
+		},
+		{
+			Name: "browse",
+			Usage: "[--unread] [limit]",
+			Description: "List the newest posts of the feeds you follow",
+			Flags: func(flags *flag.FlagSet) {
+				flags.Bool("unread", false, "only list unread posts")
+			},
+			MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerBrowse),
+		},
+		{
+			Name: "podcasts",
+			Usage: "[--unread] [limit]",
+			Description: "List the newest podcast episodes of the feeds you follow",
+			Flags: func(flags *flag.FlagSet) {
+				flags.Bool("unread", false, "only list episodes of unread posts")
+			},
+			MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerPodcasts),
+		},
+		{
+			Name: "download",
+			Usage: "[--dir path] <post-id>",
+			Description: "Download a post's enclosure, resuming an interrupted download",
+			Flags: func(flags *flag.FlagSet) {
+				flags.String("dir", ".", "directory to save the enclosure in")
+			},
+			MinArgs: 1, MaxArgs: 1,
+			Handler: HandlerDownload,
+		},
+		{
+			Name: "read",
+			Description: "Read posts in a full-screen terminal UI",
+			MaxArgs: 0,
+			Handler: MiddlewareLoggedIn(HandlerRead),
+		},
+		{
+			Name: "markread",
+			Usage: "<post-id>... | --feed <url> | --before <date>",
+			Description: "Mark posts read",
+			Flags: func(flags *flag.FlagSet) {
+				flags.String("feed", "", "mark every post of the feed with this url read")
+				flags.String("before", "", "mark posts published before this date (YYYY-MM-DD or RFC 3339) read")
+			},
+			MaxArgs: -1,
+			Handler: MiddlewareLoggedIn(HandlerMarkRead),
+		},
+		{
+			Name: "markunread",
+			Usage: "<post-id>...",
+			Description: "Mark posts unread",
+			MinArgs: 1, MaxArgs: -1,
+			Handler: MiddlewareLoggedIn(HandlerMarkUnread),
+		},
+		{
+			Name: "search",
+			Usage: "[--all] [--limit n] <query>",
+			Description: "Full-text search over posts, best matches first",
+			Flags: func(flags *flag.FlagSet) {
+				flags.Bool("all", false, "search posts of all feeds, not only followed ones")
+				flags.Int("limit", 10, "maximum number of results")
+			},
+			MinArgs: 1, MaxArgs: -1,
+			Handler: MiddlewareLoggedIn(HandlerSearch),
+		},
+		{
+			Name: "star",
+			Usage: "<post-id> [note...]",
+			Description: "Star a post, with an optional note",
+			MinArgs: 1, MaxArgs: -1,
+			Handler: MiddlewareLoggedIn(HandlerStar),
+		},
+		{
+			Name: "unstar",
+			Usage: "<post-id>...",
+			Description: "Remove stars from posts",
+			MinArgs: 1, MaxArgs: -1,
+			Handler: MiddlewareLoggedIn(HandlerUnstar),
+		},
+		{
+			Name: "starred",
+			Description: "List your starred posts and their notes",
+			MaxArgs: 0,
+			Handler: MiddlewareLoggedIn(HandlerStarred),
+		},
+		{
+			Name: "prune",
+			Usage: "<age|date>",
+			Description: "Delete unstarred posts older than a duration or date",
+			MinArgs: 1, MaxArgs: 1,
+			Handler: HandlerPrune,
+		},
+		{
+			Name: "publish",
+			Usage: "[--format rss|atom] [--limit n] [--link url] <file>",
+			Description: "Write your timeline to a file as a feed",
+			Flags: func(flags *flag.FlagSet) {
+				flags.String("format", "rss", "feed format: rss or atom")
+				flags.Int("limit", 50, "maximum number of posts")
+				flags.String("link", "", "URL the file will be served at, required for rss")
+			},
+			MinArgs: 1, MaxArgs: 1,
+			Handler: MiddlewareLoggedIn(HandlerPublish),
+		},
+		{
+			Name: "serve",
+			Usage: "[addr]",
+			Description: "Serve the JSON API, on " + defaultServeAddr + " by default",
+			MaxArgs: 1,
+			Handler: HandlerServe,
+		},
+	} {
+		commands.Register(spec)
+	}
+	return commands
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"gator/internal/config"
+	"gator/internal/database"
+	"gator/internal/memory"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newTestState returns a State on an empty memory.Store, with HOME in a
+// temporary directory so that logging in writes a throwaway config file.
+func newTestState(t *testing.T) *State {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	return &State{
+		Config: &config.Config{},
+		DB: memory.New(),
+	}
+}
+
+// loginAs registers name and makes it the current user.
+func loginAs(t *testing.T, state *State, name string) database.User {
+	t.Helper()
+	user, err := state.DB.CreateUser(context.Background(), name)
+	if err != nil { t.Fatalf("CreateUser: %v", err) }
+	state.Config.CurrentUser = name
+	return user
+}
+
+// addFeed stores a feed for user and follows it.
+func addFeed(t *testing.T, state *State, user database.User, name, url string) database.Feed {
+	t.Helper()
+	ctx := context.Background()
+	feed, err := state.DB.CreateFeed(ctx, database.CreateFeedParams{Name: name, Url: url, UserID: user.ID})
+	if err != nil { t.Fatalf("CreateFeed: %v", err) }
+	_, err = state.DB.CreateFeedFollow(ctx, database.CreateFeedFollowParams{UserID: user.ID, FeedID: feed.ID})
+	if err != nil { t.Fatalf("CreateFeedFollow: %v", err) }
+	return feed
+}
+
+// serveFeed serves body as an RSS document at the returned server's URL.
+func serveFeed(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// run runs a command line through the registry, as main does, and returns
+// what the command printed to stdout.
+func run(t *testing.T, state *State, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil { t.Fatal(err) }
+	stdout := os.Stdout
+	os.Stdout = w
+	printed := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		printed <- string(data)
+	}()
+
+	err = NewCommands().Run(state, NewCommand(args))
+	w.Close()
+	os.Stdout = stdout
+	return <-printed, err
+}
+
+// mustRun runs a command line that is expected to succeed.
+func mustRun(t *testing.T, state *State, args ...string) string {
+	t.Helper()
+	out, err := run(t, state, args...)
+	if err != nil { t.Fatalf("gator %s: %v", strings.Join(args, " "), err) }
+	return out
+}
+
+// runError runs a command line that is expected to fail with an error
+// containing want.
+func runError(t *testing.T, state *State, want string, args ...string) {
+	t.Helper()
+	_, err := run(t, state, args...)
+	if err == nil {
+		t.Fatalf("gator %s succeeded, want an error containing %q", strings.Join(args, " "), want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("gator %s: error %q, want it to contain %q", strings.Join(args, " "), err, want)
+	}
+}
+
+// assertContains fails unless out contains every one of wants.
+func assertContains(t *testing.T, out string, wants ...string) {
+	t.Helper()
+	for _, want := range wants {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+// addPost stores a post in feed and returns it.
+func addPost(t *testing.T, state *State, feed database.Feed, params database.UpsertPostParams) database.Post {
+	t.Helper()
+	params.FeedID = feed.ID
+	if params.Guid == "" {
+		params.Guid = params.Url
+	}
+	post, err := state.DB.UpsertPost(context.Background(), params)
+	if err != nil { t.Fatalf("UpsertPost: %v", err) }
+	return post
+}
+
+// interruptWhen sends SIGINT to the test process once ready returns true,
+// to stop a command that runs until interrupted. It gives up waiting, and
+// interrupts anyway, after five seconds.
+func interruptWhen(t *testing.T, ready func() bool) {
+	t.Helper()
+	go func() {
+		deadline := time.Now().Add(5 * time.Second)
+		for !ready() {
+			if time.Now().After(deadline) {
+				t.Errorf("timed out waiting for the command")
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		syscall.Kill(os.Getpid(), syscall.SIGINT)
+	}()
+}
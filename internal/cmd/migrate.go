@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/output"
+	"path/filepath"
+
+	"github.com/pressly/goose/v3"
+)
+
+// HandlerMigrate applies, rolls back or lists the schema migrations built
+// into gator.
+func HandlerMigrate(state *State, cmd Command) error {
+	if state.Migrator == nil { return fmt.Errorf("this database does not support migrations") }
+	ctx := context.Background()
+	switch cmd.Arguments[0] {
+	case "up":
+		results, err := state.Migrator.Up(ctx)
+		for _, result := range results {
+			fmt.Println(result)
+		}
+		if err != nil { return fmt.Errorf("error migrating up: %v", err) }
+		if len(results) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+		return nil
+	case "down":
+		result, err := state.Migrator.Down(ctx)
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil { return fmt.Errorf("error migrating down: %v", err) }
+		return nil
+	case "status":
+		statuses, err := state.Migrator.Status(ctx)
+		if err != nil { return fmt.Errorf("error reading migration status: %v", err) }
+		return render(cmd, "", []output.Column[*goose.MigrationStatus]{
+			{Name: "version", Value: func(status *goose.MigrationStatus) any { return status.Source.Version }},
+			{Name: "migration", Value: func(status *goose.MigrationStatus) any { return filepath.Base(status.Source.Path) }},
+			{Name: "state", Value: func(status *goose.MigrationStatus) any { return string(status.State) }},
+			{Name: "applied_at", Value: func(status *goose.MigrationStatus) any {
+				if status.State != goose.StateApplied {
+					return nil
+				}
+				return status.AppliedAt
+			}},
+		}, statuses)
+	default:
+		return fmt.Errorf("unknown migrate action %q, want up, down or status", cmd.Arguments[0])
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"gator/internal/migrate"
+	"gator/internal/sqlite"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	state := newTestState(t)
+	runError(t, state, "this database does not support migrations", "migrate", "up")
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil { t.Fatal(err) }
+	t.Cleanup(func() { db.Close() })
+	state.DB = sqlite.New(db)
+	state.Migrator, err = migrate.New(db, migrate.SQLite)
+	if err != nil { t.Fatal(err) }
+
+	// other commands refuse to run on an out-of-date schema, but still
+	// print their help
+	runError(t, state, "run 'gator migrate up'", "users")
+	out := mustRun(t, state, "users", "--help")
+	assertContains(t, out, "Usage: gator users")
+
+	out = mustRun(t, state, "--output", "csv", "migrate", "status")
+	assertContains(t, out, "1,001_schema.sql,pending,")
+	out = mustRun(t, state, "migrate", "up")
+	assertContains(t, out, "up 001_schema.sql", "up 005_post_legacy_guid.sql")
+	out = mustRun(t, state, "migrate", "up")
+	assertContains(t, out, "Schema is up to date")
+	out = mustRun(t, state, "users")
+	assertContains(t, out, "No users")
+
+	out = mustRun(t, state, "migrate", "down")
+	if out == "" {
+		t.Error("migrate down printed nothing")
+	}
+	runError(t, state, "run 'gator migrate up'", "users")
+	runError(t, state, `unknown migrate action "sideways"`, "migrate", "sideways")
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"gator/opml"
+	"os"
+	"time"
+)
+
+// HandlerImport subscribes the user to every feed in an OPML file,
+// creating the feeds that gator does not know yet. Feeds the user already
+// follows are skipped, and a feed that fails is reported without stopping
+// the import.
+func HandlerImport(state *State, cmd Command, user *database.User) error {
+	file, err := os.Open(cmd.Arguments[0])
+	if err != nil { return fmt.Errorf("error opening OPML file: %v", err) }
+	defer file.Close()
+
+	doc, err := opml.Parse(file)
+	if err != nil { return err }
+
+	var added, followed, skipped, failed int
+	for _, subscription := range doc.Subscriptions() {
+		created, err := importSubscription(context.Background(), state, user, subscription)
+		switch {
+		case errors.Is(err, errAlreadyFollowing):
+			skipped++
+			fmt.Printf("- %s (already following)\n", subscription.XMLURL)
+		case err != nil:
+			failed++
+			fmt.Printf("! %s: %v\n", subscription.XMLURL, err)
+		default:
+			if created {
+				added++
+			}
+			followed++
+			fmt.Printf("+ %s\n", subscription.XMLURL)
+		}
+	}
+
+	fmt.Printf("Added %d feeds, followed %d, skipped %d", added, followed, skipped)
+	if failed > 0 {
+		fmt.Printf(", failed %d", failed)
+	}
+	fmt.Printf("\n")
+	return nil
+}
+
+
+var errAlreadyFollowing = errors.New("already following")
+
+// importSubscription follows a single OPML subscription, reporting whether
+// the feed had to be created first.
+func importSubscription(ctx context.Context, state *State, user *database.User, subscription opml.Subscription) (bool, error) {
+	created := false
+	feed, err := state.DB.GetFeed(ctx, subscription.XMLURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		feed, err = state.DB.CreateFeed(
+			ctx,
+			database.CreateFeedParams{
+				Name: subscription.Title,
+				Url: subscription.XMLURL,
+				UserID: user.ID,
+			},
+		)
+		created = err == nil
+	}
+	if err != nil { return false, fmt.Errorf("error creating feed: %v", err) }
+
+	if created && subscription.HTMLURL != "" {
+		err = state.DB.SetFeedSiteURL(
+			ctx,
+			database.SetFeedSiteURLParams{
+				ID: feed.ID,
+				SiteUrl: sql.NullString{
+					String: subscription.HTMLURL,
+					Valid: true,
+				},
+			},
+		)
+		if err != nil { return created, fmt.Errorf("error saving site url: %v", err) }
+	}
+
+	_, err = state.DB.CreateFeedFollow(
+		ctx,
+		database.CreateFeedFollowParams{
+			UserID: user.ID,
+			FeedID: feed.ID,
+		},
+	)
+	if database.IsUniqueViolation(err) {
+		return created, errAlreadyFollowing
+	}
+	if err != nil { return created, fmt.Errorf("error following feed: %v", err) }
+
+	if subscription.Folder != "" {
+		err = state.DB.SetFeedFollowFolder(
+			ctx,
+			database.SetFeedFollowFolderParams{
+				UserID: user.ID,
+				FeedID: feed.ID,
+				Folder: sql.NullString{
+					String: subscription.Folder,
+					Valid: true,
+				},
+			},
+		)
+		if err != nil { return created, fmt.Errorf("error saving folder: %v", err) }
+	}
+	return created, nil
+}
+
+
+// HandlerExport writes the feeds the user follows as an OPML 2.0 document,
+// to the given file or to stdout, grouped by the folders they were
+// imported in.
+func HandlerExport(state *State, cmd Command, user *database.User) error {
+	feeds, err := state.DB.GetFollowedFeedsForUser(context.Background(), user.ID)
+	if err != nil { return fmt.Errorf("error listing followed feeds: %v", err) }
+
+	subscriptions := make([]opml.Subscription, 0, len(feeds))
+	for _, feed := range feeds {
+		subscriptions = append(subscriptions, opml.Subscription{
+			Title: feed.Name,
+			XMLURL: feed.Url,
+			HTMLURL: feed.SiteUrl.String,
+			Folder: feed.Folder.String,
+		})
+	}
+	doc := opml.New(fmt.Sprintf("%s's gator subscriptions", user.Name), time.Now(), subscriptions)
+
+	if len(cmd.Arguments) < 1 {
+		return doc.Write(os.Stdout)
+	}
+	file, err := os.Create(cmd.Arguments[0])
+	if err != nil { return fmt.Errorf("error creating OPML file: %v", err) }
+	err = doc.Write(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Close()
+	if err != nil { return fmt.Errorf("error writing OPML file: %v", err) }
+	fmt.Printf("Exported %d feeds to %s\n", len(subscriptions), cmd.Arguments[0])
+	return nil
+}
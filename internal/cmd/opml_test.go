@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const subscriptionsOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+<head><title>Subscriptions</title></head>
+<body>
+	<outline text="Go" title="Go">
+		<outline type="rss" text="Go Blog" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog/"/>
+	</outline>
+	<outline type="rss" text="Example" xmlUrl="https://example.com/feed"/>
+</body>
+</opml>`
+
+func TestImportAndExport(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	addFeed(t, state, user, "Example", "https://example.com/feed")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "subscriptions.opml")
+	err := os.WriteFile(path, []byte(subscriptionsOPML), 0o644)
+	if err != nil { t.Fatal(err) }
+
+	out := mustRun(t, state, "import", path)
+	assertContains(t, out,
+		"+ https://go.dev/blog/feed.atom",
+		"- https://example.com/feed (already following)",
+		"Added 1 feeds, followed 1, skipped 1",
+	)
+
+	// exporting keeps the folder and site url from the import
+	out = mustRun(t, state, "export")
+	assertContains(t, out,
+		`<title>alice&#39;s gator subscriptions</title>`,
+		`text="Go"`,
+		`xmlUrl="https://go.dev/blog/feed.atom"`,
+		`htmlUrl="https://go.dev/blog/"`,
+		`xmlUrl="https://example.com/feed"`,
+	)
+
+	exported := filepath.Join(dir, "export.opml")
+	out = mustRun(t, state, "export", exported)
+	assertContains(t, out, "Exported 2 feeds to "+exported)
+	data, err := os.ReadFile(exported)
+	if err != nil { t.Fatal(err) }
+	if !strings.Contains(string(data), "https://go.dev/blog/feed.atom") {
+		t.Errorf("exported file is missing the imported feed:\n%s", data)
+	}
+
+	// another user importing the same file follows the existing feeds
+	loginAs(t, state, "bob")
+	out = mustRun(t, state, "import", exported)
+	assertContains(t, out, "Added 0 feeds, followed 2, skipped 0")
+
+	runError(t, state, "error opening OPML file", "import", filepath.Join(dir, "missing.opml"))
+	notOPML := filepath.Join(dir, "feed.xml")
+	err = os.WriteFile(notOPML, []byte("<rss"), 0o644)
+	if err != nil { t.Fatal(err) }
+	_, err = run(t, state, "import", notOPML)
+	if err == nil { t.Error("importing a file that is not OPML succeeded") }
+	runError(t, state, "error creating OPML file", "export", filepath.Join(dir, "missing", "export.opml"))
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"fmt"
+	"gator/internal/output"
+	"os"
+)
+
+// render writes a listing to stdout in the --output format of cmd. An
+// empty listing is reported with the empty message when shown as a table.
+func render[T any](cmd Command, empty string, columns []output.Column[T], rows []T) error {
+	format, err := output.ParseFormat(cmd.String("output"))
+	if err != nil { return err }
+	if len(rows) == 0 && format == output.Table && empty != "" {
+		fmt.Println(empty)
+		return nil
+	}
+	return output.Write(os.Stdout, format, columns, rows)
+}
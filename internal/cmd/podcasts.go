@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/download"
+	"gator/internal/output"
+	"mime"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HandlerPodcasts lists the audio and video enclosures of the feeds the
+// user follows, newest first.
+func HandlerPodcasts(state *State, cmd Command, user *database.User) error {
+	var limit int32 = 10
+	if len(cmd.Arguments) > 0 {
+		num, err := strconv.Atoi(cmd.Arguments[0])
+		if err != nil || num < 1 { return fmt.Errorf("invalid limit: %s", cmd.Arguments[0]) }
+		limit = int32(num)
+	}
+	episodes, err := state.DB.GetPodcastEpisodesForUser(
+		context.Background(),
+		database.GetPodcastEpisodesForUserParams{
+			UserID: user.ID,
+			UnreadOnly: cmd.Bool("unread"),
+			Limit: limit,
+		},
+	)
+	if err != nil { return fmt.Errorf("error listing podcast episodes: %v", err) }
+	return render(cmd, "No podcast episodes", []output.Column[database.GetPodcastEpisodesForUserRow]{
+		{Name: "id", Value: func(episode database.GetPodcastEpisodesForUserRow) any { return episode.PostID }},
+		{Name: "read", Value: func(episode database.GetPodcastEpisodesForUserRow) any { return episode.Read }},
+		{Name: "feed", Value: func(episode database.GetPodcastEpisodesForUserRow) any { return episode.FeedName }},
+		{Name: "episode", Value: func(episode database.GetPodcastEpisodesForUserRow) any {
+			if !episode.Episode.Valid {
+				return nil
+			}
+			return episode.Episode.Int32
+		}},
+		{Name: "title", Value: func(episode database.GetPodcastEpisodesForUserRow) any { return episode.Title }},
+		{Name: "published_at", Value: func(episode database.GetPodcastEpisodesForUserRow) any { return output.NullTime(episode.PublishedAt) }},
+		{Name: "duration", Value: func(episode database.GetPodcastEpisodesForUserRow) any {
+			if !episode.DurationSeconds.Valid {
+				return nil
+			}
+			return formatDuration(time.Duration(episode.DurationSeconds.Int32) * time.Second)
+		}},
+		{Name: "size", Value: func(episode database.GetPodcastEpisodesForUserRow) any {
+			if !episode.Length.Valid {
+				return nil
+			}
+			return episode.Length.Int64
+		}},
+		{Name: "url", Value: func(episode database.GetPodcastEpisodesForUserRow) any { return episode.Url }},
+	}, episodes)
+}
+
+
+// HandlerDownload saves the enclosure of a post into --dir. Interrupted
+// downloads are resumed by running the command again.
+func HandlerDownload(state *State, cmd Command) error {
+	ids, err := parsePostIDs(cmd.Arguments)
+	if err != nil { return err }
+	postID := ids[0]
+
+	enclosures, err := state.DB.GetEnclosuresForPost(context.Background(), postID)
+	if err != nil { return fmt.Errorf("error reading enclosures: %v", err) }
+	enclosure, ok := playableEnclosure(enclosures)
+	if !ok { return fmt.Errorf("post %s has no enclosure", postID) }
+
+	dir := cmd.String("dir")
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil { return fmt.Errorf("error creating %s: %v", dir, err) }
+	path := filepath.Join(dir, enclosureFileName(postID, enclosure))
+	if _, err := os.Stat(path); err == nil {
+		fmt.Printf("Already downloaded: %s\n", path)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("Downloading %s\n", enclosure.Url)
+	result, err := download.Fetch(ctx, enclosure.Url, path)
+	if err != nil { return err }
+	if result.Resumed > 0 {
+		fmt.Printf("Resumed after %d bytes\n", result.Resumed)
+	}
+	fmt.Printf("Saved %s (%d bytes)\n", path, result.Size)
+	return nil
+}
+
+
+// playableEnclosure picks the first audio or video enclosure, or the first
+// of unknown type, falling back to the first enclosure at all.
+func playableEnclosure(enclosures []database.Enclosure) (database.Enclosure, bool) {
+	for _, enclosure := range enclosures {
+		mimeType := enclosure.MimeType.String
+		if !enclosure.MimeType.Valid || strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/") {
+			return enclosure, true
+		}
+	}
+	if len(enclosures) > 0 {
+		return enclosures[0], true
+	}
+	return database.Enclosure{}, false
+}
+
+
+// enclosureFileName is the file name of the enclosure URL, prefixed with
+// the start of the post id as podcasts often reuse names like
+// episode.mp3.
+func enclosureFileName(postID uuid.UUID, enclosure database.Enclosure) string {
+	name := ""
+	if u, err := url.Parse(enclosure.Url); err == nil {
+		name = path.Base(u.Path)
+	}
+	if name == "" || name == "." || name == "/" {
+		name = "enclosure"
+		if extensions, _ := mime.ExtensionsByType(enclosure.MimeType.String); len(extensions) > 0 {
+			name += extensions[0]
+		}
+	}
+	return postID.String()[:8] + "-" + name
+}
+
+
+// formatDuration writes d as H:MM:SS, or M:SS under an hour.
+func formatDuration(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
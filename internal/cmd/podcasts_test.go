@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const episodeAudio = "not really an mp3"
+
+// addEpisode stores a post in feed with an audio enclosure at url.
+func addEpisode(t *testing.T, state *State, feed database.Feed, title, url string) database.Post {
+	t.Helper()
+	post := addPost(t, state, feed, database.UpsertPostParams{Title: title, Url: "https://example.com/" + title})
+	err := state.DB.UpsertEnclosure(context.Background(), database.UpsertEnclosureParams{
+		Url: url,
+		MimeType: sql.NullString{String: "audio/mpeg", Valid: true},
+		Length: sql.NullInt64{Int64: int64(len(episodeAudio)), Valid: true},
+		DurationSeconds: sql.NullInt32{Int32: 3725, Valid: true},
+		Episode: sql.NullInt32{Int32: 7, Valid: true},
+		FeedID: feed.ID,
+		Guid: post.Guid,
+	})
+	if err != nil { t.Fatalf("UpsertEnclosure: %v", err) }
+	return post
+}
+
+func TestPodcasts(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	feed := addFeed(t, state, user, "Show", "https://example.com/podcast")
+
+	out := mustRun(t, state, "podcasts")
+	assertContains(t, out, "No podcast episodes")
+
+	post := addEpisode(t, state, feed, "Pilot", "https://example.com/pilot.mp3")
+	addPost(t, state, feed, database.UpsertPostParams{Title: "Show notes", Url: "https://example.com/notes"})
+	out = mustRun(t, state, "--output", "csv", "podcasts")
+	assertContains(t, out, "Show,7,Pilot,,1:02:05,17,https://example.com/pilot.mp3")
+
+	mustRun(t, state, "markread", post.ID.String())
+	out = mustRun(t, state, "podcasts", "--unread")
+	assertContains(t, out, "No podcast episodes")
+
+	runError(t, state, "invalid limit", "podcasts", "none")
+}
+
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pilot.mp3" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "pilot.mp3", time.Time{}, strings.NewReader(episodeAudio))
+	}))
+	t.Cleanup(server.Close)
+
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	feed := addFeed(t, state, user, "Show", "https://example.com/podcast")
+	post := addEpisode(t, state, feed, "Pilot", server.URL+"/pilot.mp3")
+	dir := filepath.Join(t.TempDir(), "episodes")
+	path := filepath.Join(dir, post.ID.String()[:8]+"-pilot.mp3")
+
+	out := mustRun(t, state, "download", "--dir", dir, post.ID.String())
+	assertContains(t, out, "Saved "+path+" (17 bytes)")
+	data, err := os.ReadFile(path)
+	if err != nil { t.Fatal(err) }
+	if string(data) != episodeAudio {
+		t.Errorf("downloaded %q, want %q", data, episodeAudio)
+	}
+	out = mustRun(t, state, "download", "--dir", dir, post.ID.String())
+	assertContains(t, out, "Already downloaded: "+path)
+
+	notes := addPost(t, state, feed, database.UpsertPostParams{Title: "Show notes", Url: "https://example.com/notes"})
+	runError(t, state, "has no enclosure", "download", "--dir", dir, notes.ID.String())
+	runError(t, state, "has no enclosure", "download", "--dir", dir, uuid.NewString())
+	runError(t, state, `invalid post id "pilot"`, "download", "pilot")
+
+	missing := addEpisode(t, state, feed, "Lost", server.URL+"/lost.mp3")
+	_, err = run(t, state, "download", "--dir", dir, missing.ID.String())
+	if err == nil { t.Error("downloading a missing enclosure succeeded") }
+}
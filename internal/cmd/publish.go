@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/publish"
+	"gator/rss"
+	"os"
+)
+
+// HandlerPublish writes the user's timeline to a file as an RSS 2.0 or
+// Atom feed, so other tools can subscribe to it.
+func HandlerPublish(state *State, cmd Command, user *database.User) error {
+	path := cmd.Arguments[0]
+	format := cmd.String("format")
+	write := rss.WriteRSS
+	switch format {
+	case "rss":
+	case "atom":
+		write = rss.WriteAtom
+	default:
+		return fmt.Errorf("unknown feed format %q, expected rss or atom", format)
+	}
+	if format == "rss" && cmd.String("link") == "" {
+		return fmt.Errorf("--link is required for RSS feeds, which must link to where they are served")
+	}
+	limit, err := limitFlag(cmd)
+	if err != nil { return err }
+
+	out, err := publish.UserFeed(context.Background(), state.DB, user, limit, cmd.String("link"))
+	if err != nil { return err }
+
+	file, err := os.Create(path)
+	if err != nil { return fmt.Errorf("error creating feed file: %v", err) }
+	err = write(file, out)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Close()
+	if err != nil { return fmt.Errorf("error writing feed file: %v", err) }
+	fmt.Printf("Published %d posts to %s\n", len(out.Items), path)
+	return nil
+}
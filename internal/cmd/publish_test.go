@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"gator/rss"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	seedPosts(t, state, user)
+	dir := t.TempDir()
+
+	for _, format := range []string{"rss", "atom"} {
+		path := filepath.Join(dir, "timeline."+format)
+		out := mustRun(t, state, "publish", "--format", format, "--limit", "2", "--link", "https://example.org/timeline", path)
+		assertContains(t, out, "Published 2 posts to "+path)
+
+		data, err := os.ReadFile(path)
+		if err != nil { t.Fatal(err) }
+		feed, err := rss.ParseFeed(data)
+		if err != nil { t.Fatalf("%s: %v", format, err) }
+		if feed.Channel.Link != "https://example.org/timeline" {
+			t.Errorf("%s: link = %q, want the --link", format, feed.Channel.Link)
+		}
+		if len(feed.Channel.Item) != 2 || feed.Channel.Item[0].Title != "Third" {
+			t.Errorf("%s: items = %+v, want Third then Second", format, feed.Channel.Item)
+		}
+	}
+
+	// Atom feeds may leave out the link
+	mustRun(t, state, "publish", "--format", "atom", filepath.Join(dir, "unlinked.atom"))
+
+	runError(t, state, "--link is required for RSS feeds", "publish", filepath.Join(dir, "unlinked.rss"))
+	for _, limit := range []string{"0", "-5", "4294967296"} {
+		runError(t, state, "invalid limit: "+limit, "publish", "--format", "atom", "--limit", limit, filepath.Join(dir, "limited.atom"))
+	}
+	runError(t, state, `unknown feed format "json"`, "publish", "--format", "json", filepath.Join(dir, "timeline.json"))
+	runError(t, state, "error creating feed file", "publish", "--format", "atom", filepath.Join(dir, "missing", "timeline.atom"))
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HandlerMarkRead marks posts read for the user: the posts given by id,
+// every post of a feed with --feed, or every post from followed feeds
+// published before a date with --before.
+func HandlerMarkRead(state *State, cmd Command, user *database.User) error {
+	feedURL := cmd.String("feed")
+	before := cmd.String("before")
+	if feedURL != "" && before != "" {
+		return fmt.Errorf("--feed and --before cannot be used together")
+	}
+	if (feedURL != "" || before != "") && len(cmd.Arguments) > 0 {
+		return fmt.Errorf("post ids cannot be used with --feed or --before")
+	}
+
+	switch {
+	case feedURL != "":
+		feed, err := state.GetFeedByURL(feedURL)
+		if err != nil { return err }
+		count, err := state.DB.MarkFeedRead(
+			context.Background(),
+			database.MarkFeedReadParams{
+				UserID: user.ID,
+				FeedID: feed.ID,
+			},
+		)
+		if err != nil { return fmt.Errorf("error marking feed read: %v", err) }
+		fmt.Printf("Marked %d posts of %s read\n", count, feed.Name)
+	case before != "":
+		date, err := parseDateArg(before)
+		if err != nil { return err }
+		count, err := state.DB.MarkPostsReadBefore(
+			context.Background(),
+			database.MarkPostsReadBeforeParams{
+				UserID: user.ID,
+				Before: date,
+			},
+		)
+		if err != nil { return fmt.Errorf("error marking posts read: %v", err) }
+		fmt.Printf("Marked %d posts published before %s read\n", count, date.Format(time.DateOnly))
+	default:
+		ids, err := parsePostIDs(cmd.Arguments)
+		if err != nil { return err }
+		for _, id := range ids {
+			err = state.DB.MarkPostRead(
+				context.Background(),
+				database.MarkPostReadParams{
+					UserID: user.ID,
+					PostID: id,
+				},
+			)
+			if err != nil { return fmt.Errorf("error marking post %s read: %v", id, err) }
+		}
+		fmt.Printf("Marked %d posts read\n", len(ids))
+	}
+	return nil
+}
+
+
+func HandlerMarkUnread(state *State, cmd Command, user *database.User) error {
+	ids, err := parsePostIDs(cmd.Arguments)
+	if err != nil { return err }
+	for _, id := range ids {
+		err = state.DB.MarkPostUnread(
+			context.Background(),
+			database.MarkPostUnreadParams{
+				UserID: user.ID,
+				PostID: id,
+			},
+		)
+		if err != nil { return fmt.Errorf("error marking post %s unread: %v", id, err) }
+	}
+	fmt.Printf("Marked %d posts unread\n", len(ids))
+	return nil
+}
+
+
+func parsePostIDs(args []string) ([]uuid.UUID, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("missing post id")
+	}
+	ids := make([]uuid.UUID, 0, len(args))
+	for _, arg := range args {
+		id, err := uuid.Parse(arg)
+		if err != nil { return nil, fmt.Errorf("invalid post id %q", arg) }
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+
+func parseDateArg(value string) (time.Time, error) {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value)
+}
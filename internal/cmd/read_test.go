@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// seedPosts stores a followed feed with a post on each of the first three
+// days of 2026 and returns them oldest first.
+func seedPosts(t *testing.T, state *State, user database.User) (database.Feed, []database.Post) {
+	t.Helper()
+	feed := addFeed(t, state, user, "Blog", "https://example.com/feed")
+	var posts []database.Post
+	for i, title := range []string{"First", "Second", "Third"} {
+		posts = append(posts, addPost(t, state, feed, database.UpsertPostParams{
+			Title: title,
+			Url: "https://example.com/" + title,
+			Description: sql.NullString{String: title + " post about gophers", Valid: true},
+			PublishedAt: sql.NullTime{Time: time.Date(2026, 1, 1+i, 12, 0, 0, 0, time.UTC), Valid: true},
+		}))
+	}
+	return feed, posts
+}
+
+// readTitles returns whether each of the user's posts is read, by title.
+func readTitles(t *testing.T, state *State, user database.User) map[string]bool {
+	t.Helper()
+	posts, err := state.DB.GetPostsForUser(context.Background(), database.GetPostsForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil { t.Fatalf("GetPostsForUser: %v", err) }
+	read := make(map[string]bool)
+	for _, post := range posts {
+		read[post.Title] = post.Read
+	}
+	return read
+}
+
+func TestMarkRead(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	_, posts := seedPosts(t, state, user)
+
+	out := mustRun(t, state, "markread", posts[0].ID.String())
+	assertContains(t, out, "Marked 1 posts read")
+	if read := readTitles(t, state, user); !read["First"] || read["Second"] {
+		t.Errorf("read = %v, want only First", read)
+	}
+
+	out = mustRun(t, state, "markunread", posts[0].ID.String())
+	assertContains(t, out, "Marked 1 posts unread")
+	if read := readTitles(t, state, user); read["First"] {
+		t.Errorf("read = %v, want none", read)
+	}
+
+	out = mustRun(t, state, "markread", "--before", "2026-01-02T12:00:01Z")
+	assertContains(t, out, "Marked 2 posts published before 2026-01-02 read")
+	if read := readTitles(t, state, user); !read["First"] || !read["Second"] || read["Third"] {
+		t.Errorf("read = %v, want First and Second", read)
+	}
+
+	out = mustRun(t, state, "markread", "--feed", "https://example.com/feed")
+	assertContains(t, out, "of Blog read")
+	if read := readTitles(t, state, user); !read["Third"] {
+		t.Errorf("read = %v, want every post", read)
+	}
+
+	runError(t, state, "missing post id", "markread")
+	runError(t, state, `invalid post id "42"`, "markread", "42")
+	runError(t, state, "error marking post", "markread", uuid.NewString())
+	runError(t, state, "invalid date", "markread", "--before", "yesterday")
+	runError(t, state, "error getting feed", "markread", "--feed", "https://example.com/missing")
+	runError(t, state, "--feed and --before cannot be used together", "markread", "--feed", "https://example.com/feed", "--before", "2026-01-02")
+	runError(t, state, "post ids cannot be used with --feed or --before", "markread", "--feed", "https://example.com/feed", posts[0].ID.String())
+	runError(t, state, "post ids cannot be used with --feed or --before", "markread", "--before", "2026-01-02", posts[0].ID.String())
+	runError(t, state, `invalid post id "42"`, "markunread", "42")
+	runError(t, state, "expected at least 1 argument", "markunread")
+}
+
+func TestMarkFeedReadNeedsAFollow(t *testing.T) {
+	state := newTestState(t)
+	alice := loginAs(t, state, "alice")
+	feed, _ := seedPosts(t, state, alice)
+	bob := loginAs(t, state, "bob")
+
+	out := mustRun(t, state, "markread", "--feed", "https://example.com/feed")
+	assertContains(t, out, "Marked 0 posts of Blog read")
+
+	_, err := state.DB.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{UserID: bob.ID, FeedID: feed.ID})
+	if err != nil { t.Fatalf("CreateFeedFollow: %v", err) }
+	if read := readTitles(t, state, bob); len(read) != 3 || read["First"] || read["Second"] || read["Third"] {
+		t.Errorf("read = %v, want every post unread", read)
+	}
+}
+
+func TestReadNeedsATerminal(t *testing.T) {
+	state := newTestState(t)
+	loginAs(t, state, "alice")
+
+	stdin := os.Stdin
+	t.Cleanup(func() { os.Stdin = stdin })
+	devNull, err := os.Open(os.DevNull)
+	if err != nil { t.Fatal(err) }
+	defer devNull.Close()
+	os.Stdin = devNull
+
+	runError(t, state, "read needs an interactive terminal", "read")
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import (
+	"context"
+	"gator/internal/database"
+	"gator/internal/reader"
+)
+
+// HandlerRead opens the full-screen reader on the user's followed feeds.
+func HandlerRead(state *State, cmd Command, user *database.User) error {
+	return reader.Run(context.Background(), state.DB, user)
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"gator/internal/output"
+	"io"
+	"os"
+	"strings"
+)
+
+// Spec declares a command: how it is invoked, what it does, the flags it
+// accepts and how many positional arguments it takes. MaxArgs of -1 means
+// no upper bound.
+type Spec struct {
+	Name string
+	Usage string
+	Description string
+	Flags func(flags *flag.FlagSet)
+	MinArgs int
+	MaxArgs int
+	Handler func(*State, Command) error
+}
+
+type Commands struct {
+	Commands map[string]*Spec
+	order []string
+}
+
+func (c *Commands) Register(spec Spec) {
+	if c.Commands == nil {
+		c.Commands = make(map[string]*Spec)
+	}
+	if _, ok := c.Commands[spec.Name]; !ok {
+		c.order = append(c.order, spec.Name)
+	}
+	c.Commands[spec.Name] = &spec
+}
+
+// Run parses the flags and arguments of cmd against its spec and only
+// calls the handler once they are valid. --help prints the command's help
+// instead.
+func (c *Commands) Run(s *State, cmd Command) error {
+	if cmd.Name == "help" || cmd.Name == "--help" || cmd.Name == "-h" {
+		return c.help(os.Stdout, cmd.Arguments)
+	}
+	spec, ok := c.Commands[cmd.Name]
+	if !ok { return fmt.Errorf("unknown command: %s (run 'gator help' for a list)", cmd.Name) }
+
+	flags := spec.flagSet()
+	args, err := parseInterspersed(flags, cmd.Arguments)
+	if errors.Is(err, flag.ErrHelp) {
+		spec.printHelp(os.Stdout)
+		return nil
+	}
+	if err != nil { return spec.usageError("%v", err) }
+	_, err = output.ParseFormat(flags.Lookup("output").Value.String())
+	if err != nil { return spec.usageError("%v", err) }
+
+	if len(args) < spec.MinArgs {
+		return spec.usageError("expected at least %d %s, got %d", spec.MinArgs, plural(spec.MinArgs, "argument"), len(args))
+	}
+	if spec.MaxArgs >= 0 && len(args) > spec.MaxArgs {
+		return spec.usageError("expected at most %d %s, got %d", spec.MaxArgs, plural(spec.MaxArgs, "argument"), len(args))
+	}
+
+	// commands other than migrate would only fail with SQL errors on an
+	// out-of-date schema
+	if spec.Name != "migrate" && s.Migrator != nil {
+		err := s.Migrator.Check(context.Background())
+		if err != nil { return err }
+	}
+
+	cmd.Arguments = args
+	cmd.Flags = flags
+	return spec.Handler(s, cmd)
+}
+
+
+// NewCommand builds the command to run from the command line. Global
+// flags such as --output may come before the command name.
+func NewCommand(args []string) Command {
+	var global []string
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		if strings.Contains(args[0], "=") || len(args) == 1 {
+			global = append(global, args[0])
+			args = args[1:]
+		} else {
+			global = append(global, args[:2]...)
+			args = args[2:]
+		}
+	}
+	if len(args) == 0 {
+		return Command{Arguments: global}
+	}
+	return Command{Name: args[0], Arguments: append(global, args[1:]...)}
+}
+
+
+// PrintUsage writes the list of commands to w.
+func (c *Commands) PrintUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: gator <command> [flags] [arguments]\n\nCommands:\n")
+	width := 0
+	for _, name := range c.order {
+		width = max(width, len(name))
+	}
+	for _, name := range c.order {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, name, c.Commands[name].Description)
+	}
+	fmt.Fprintf(w, "\nGlobal flags:\n  --output format  output format of listings: table, json, jsonl or csv\n")
+	fmt.Fprintf(w, "\nRun 'gator help <command>' or 'gator <command> --help' for details.\n")
+}
+
+
+func (c *Commands) help(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		c.PrintUsage(w)
+		return nil
+	}
+	spec, ok := c.Commands[args[0]]
+	if !ok { return fmt.Errorf("unknown command: %s (run 'gator help' for a list)", args[0]) }
+	spec.printHelp(w)
+	return nil
+}
+
+
+func (spec *Spec) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet(spec.Name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Usage = func() {}
+	flags.String("output", string(output.Table), "output format of listings: table, json, jsonl or csv")
+	if spec.Flags != nil {
+		spec.Flags(flags)
+	}
+	return flags
+}
+
+
+func (spec *Spec) usageLine() string {
+	line := "gator " + spec.Name
+	if spec.Usage != "" {
+		line += " " + spec.Usage
+	}
+	return line
+}
+
+
+func (spec *Spec) printHelp(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", spec.usageLine(), spec.Description)
+	flags := spec.flagSet()
+	hasFlags := false
+	flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(w, "\nFlags:\n")
+		flags.SetOutput(w)
+		flags.PrintDefaults()
+	}
+}
+
+
+func (spec *Spec) usageError(format string, args ...any) error {
+	return fmt.Errorf("%s: %s\nusage: %s", spec.Name, fmt.Sprintf(format, args...), spec.usageLine())
+}
+
+
+// parseInterspersed parses flags wherever they appear among the
+// positional arguments, which it returns. Everything after "--" is
+// positional.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		err := flags.Parse(args)
+		if err != nil { return nil, err }
+		rest := flags.Args()
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+
+// Bool returns the value of a boolean flag declared by the command's spec.
+func (cmd Command) Bool(name string) bool {
+	return cmd.flagValue(name).(bool)
+}
+
+func (cmd Command) Int(name string) int {
+	return cmd.flagValue(name).(int)
+}
+
+func (cmd Command) String(name string) string {
+	return cmd.flagValue(name).(string)
+}
+
+func (cmd Command) flagValue(name string) any {
+	if cmd.Flags == nil {
+		panic(fmt.Sprintf("command %s has no flags", cmd.Name))
+	}
+	f := cmd.Flags.Lookup(name)
+	if f == nil {
+		panic(fmt.Sprintf("command %s has no flag --%s", cmd.Name, name))
+	}
+	return f.Value.(flag.Getter).Get()
+}
+
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gator/internal/database"
+	"gator/rss"
+	"math"
+	"time"
+)
+
+// adaptiveHistory is how many recent posts the posting cadence of a feed
+// is learned from.
+const adaptiveHistory = 20
+
+// maxIntervalSeconds is the longest interval the int32 interval columns
+// hold.
+const maxIntervalSeconds = math.MaxInt32
+
+// effectiveInterval is how long a feed waits between successful fetches:
+// the user's override if set, otherwise the interval learned from its
+// posting cadence, never shorter than the feed's own hint. Feeds with
+// none of these are due again immediately and polled round-robin.
+func effectiveInterval(feed *database.Feed) time.Duration {
+	if feed.IntervalOverrideSeconds.Valid {
+		return time.Duration(feed.IntervalOverrideSeconds.Int32) * time.Second
+	}
+	interval := time.Duration(0)
+	if feed.AdaptiveIntervalSeconds.Valid {
+		interval = time.Duration(feed.AdaptiveIntervalSeconds.Int32) * time.Second
+	}
+	if feed.HintedIntervalSeconds.Valid {
+		interval = max(interval, time.Duration(feed.HintedIntervalSeconds.Int32)*time.Second)
+	}
+	return interval
+}
+
+
+// nextFetchTime schedules the feed one interval after from, moved forward
+// to the first hour that is not in the feed's skipHours or skipDays. Those
+// are GMT, so hours are counted in UTC whatever the local time zone.
+func nextFetchTime(feed *database.Feed, from time.Time) time.Time {
+	next := from.Add(effectiveInterval(feed)).UTC()
+	for range 7 * 24 {
+		hourSkipped := feed.SkipHours&(1<<next.Hour()) != 0
+		daySkipped := feed.SkipDays&(1<<int(next.Weekday())) != 0
+		if !hourSkipped && !daySkipped {
+			return next
+		}
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+	// every hour of the week is skipped, which no feed means literally
+	return from.Add(effectiveInterval(feed)).UTC()
+}
+
+
+func updateFeedSchedule(ctx context.Context, state *State, feed *database.Feed, schedule rss.Schedule) error {
+	_, maxInterval, err := state.Config.PollBounds()
+	if err != nil { return err }
+
+	// the hint comes from the feed, so it is held to poll_max_interval
+	// like the adaptive interval
+	hint := min(schedule.Interval, maxInterval, maxIntervalSeconds*time.Second)
+	params := database.UpdateFeedScheduleParams{
+		ID: feed.ID,
+		HintedIntervalSeconds: sql.NullInt32{
+			Int32: int32(hint / time.Second),
+			Valid: hint > 0,
+		},
+	}
+	for _, hour := range schedule.SkipHours {
+		params.SkipHours |= 1 << hour
+	}
+	for _, day := range schedule.SkipDays {
+		params.SkipDays |= 1 << int(day)
+	}
+
+	err = state.DB.UpdateFeedSchedule(ctx, params)
+	if err != nil { return fmt.Errorf("error saving feed schedule: %v", err) }
+
+	feed.HintedIntervalSeconds = params.HintedIntervalSeconds
+	feed.SkipHours = params.SkipHours
+	feed.SkipDays = params.SkipDays
+	return nil
+}
+
+
+func updateAdaptiveInterval(ctx context.Context, state *State, feed *database.Feed) error {
+	minInterval, maxInterval, err := state.Config.PollBounds()
+	if err != nil { return err }
+
+	dates, err := state.DB.GetFeedPublishDates(
+		ctx,
+		database.GetFeedPublishDatesParams{
+			FeedID: feed.ID,
+			Limit: adaptiveHistory,
+		},
+	)
+	if err != nil { return fmt.Errorf("error reading post history: %v", err) }
+
+	interval := sql.NullInt32{}
+	cadence, ok := postingCadence(dates, time.Now())
+	if ok {
+		cadence = min(max(cadence, minInterval), maxInterval, maxIntervalSeconds*time.Second)
+		interval = sql.NullInt32{
+			Int32: int32(cadence / time.Second),
+			Valid: true,
+		}
+	}
+
+	err = state.DB.SetFeedAdaptiveInterval(
+		ctx,
+		database.SetFeedAdaptiveIntervalParams{
+			ID: feed.ID,
+			AdaptiveIntervalSeconds: interval,
+		},
+	)
+	if err != nil { return fmt.Errorf("error saving adaptive interval: %v", err) }
+
+	feed.AdaptiveIntervalSeconds = interval
+	return nil
+}
+
+
+// postingCadence is the mean time between posts, measured from the oldest
+// date given up to now so that a feed that has gone quiet slows down too.
+// It needs at least two dates.
+func postingCadence(dates []sql.NullTime, now time.Time) (time.Duration, bool) {
+	oldest := now
+	count := 0
+	for _, date := range dates {
+		if !date.Valid || date.Time.After(now) {
+			continue
+		}
+		count++
+		if date.Time.Before(oldest) {
+			oldest = date.Time
+		}
+	}
+	if count < 2 {
+		return 0, false
+	}
+	return now.Sub(oldest) / time.Duration(count), true
+}
+
+
+func HandlerFeedInfo(state *State, cmd Command) error {
+	feed, err := state.GetFeedByURL(cmd.Arguments[0])
+	if err != nil { return err }
+
+	fmt.Printf("Name:              %s\n", feed.Name)
+	fmt.Printf("URL:               %s\n", feed.Url)
+	fmt.Printf("Last fetched:      %s\n", formatNullTime(feed.LastFeteched))
+	fmt.Printf("Last success:      %s\n", formatNullTime(feed.LastSuccessAt))
+	fmt.Printf("Next fetch:        %s\n", formatNullTime(feed.NextFetchAt))
+	fmt.Printf("Failures:          %d\n", feed.ConsecutiveFailures)
+	fmt.Printf("Feed hint:         %s\n", formatSeconds(feed.HintedIntervalSeconds))
+	fmt.Printf("Adaptive interval: %s\n", formatSeconds(feed.AdaptiveIntervalSeconds))
+	fmt.Printf("Override:          %s\n", formatSeconds(feed.IntervalOverrideSeconds))
+	polled := "each agg tick"
+	if interval := effectiveInterval(feed); interval > 0 {
+		polled = interval.String()
+	}
+	fmt.Printf("Polled every:      %s\n", polled)
+	return nil
+}
+
+
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return "never"
+	}
+	return t.Time.Format(time.RFC1123)
+}
+
+
+func formatSeconds(seconds sql.NullInt32) string {
+	if !seconds.Valid {
+		return "none"
+	}
+	return (time.Duration(seconds.Int32) * time.Second).String()
+}
+
+
+func HandlerSetInterval(state *State, cmd Command) error {
+	feed, err := state.GetFeedByURL(cmd.Arguments[0])
+	if err != nil { return err }
+
+	override := sql.NullInt32{}
+	if cmd.Arguments[1] != "auto" {
+		interval, err := time.ParseDuration(cmd.Arguments[1])
+		if err != nil { return fmt.Errorf("error parsing duration: %v", err) }
+		if interval < time.Second {
+			return fmt.Errorf("interval must be at least one second")
+		}
+		if interval > maxIntervalSeconds*time.Second {
+			return fmt.Errorf("interval must be at most %s", maxIntervalSeconds*time.Second)
+		}
+		override = sql.NullInt32{
+			Int32: int32(interval / time.Second),
+			Valid: true,
+		}
+	}
+
+	err = state.DB.SetFeedIntervalOverride(
+		context.Background(),
+		database.SetFeedIntervalOverrideParams{
+			ID: feed.ID,
+			IntervalOverrideSeconds: override,
+		},
+	)
+	if err != nil { return fmt.Errorf("error setting feed interval: %v", err) }
+
+	if override.Valid {
+		fmt.Printf("%s is now fetched every %s\n", feed.Name, time.Duration(override.Int32)*time.Second)
+	} else {
+		fmt.Printf("%s is now fetched on its own schedule\n", feed.Name)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"testing"
+	"time"
+)
+
+func TestNextFetchTimeSkipsGMTHours(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	feed := &database.Feed{
+		IntervalOverrideSeconds: sql.NullInt32{Int32: 3600, Valid: true},
+		// 00:00 to 05:59 GMT
+		SkipHours: 1<<0 | 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5,
+	}
+
+	// 08:30 in Tokyo is 23:30 GMT, so one hour later falls in 00:00 GMT
+	from := time.Date(2026, 1, 2, 8, 30, 0, 0, tokyo)
+	next := nextFetchTime(feed, from)
+	want := time.Date(2026, 1, 2, 6, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Errorf("nextFetchTime = %v, want %v", next, want)
+	}
+}
+
+func TestNextFetchTimeSkipsGMTDays(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+	feed := &database.Feed{
+		IntervalOverrideSeconds: sql.NullInt32{Int32: 3600, Valid: true},
+		SkipDays: 1 << int(time.Saturday),
+	}
+
+	// an hour after 21:00 on Friday in New York is 03:00 on Saturday GMT
+	from := time.Date(2026, 1, 2, 21, 0, 0, 0, newYork)
+	next := nextFetchTime(feed, from)
+	want := time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Errorf("nextFetchTime = %v, want %v", next, want)
+	}
+}
+
+func TestNextFetchTimeWithoutSkips(t *testing.T) {
+	feed := &database.Feed{
+		HintedIntervalSeconds: sql.NullInt32{Int32: 1800, Valid: true},
+	}
+	from := time.Date(2026, 1, 2, 8, 30, 0, 0, time.Local)
+	next := nextFetchTime(feed, from)
+	if want := from.Add(30 * time.Minute); !next.Equal(want) {
+		t.Errorf("nextFetchTime = %v, want %v", next, want)
+	}
+}
+
+func TestFeedSchedulingCommands(t *testing.T) {
+	ctx := context.Background()
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	feed := addFeed(t, state, user, "Blog", "https://example.com/feed")
+
+	out := mustRun(t, state, "feedinfo", feed.Url)
+	assertContains(t, out, "Name:              Blog", "Last fetched:      never", "Polled every:      each agg tick")
+
+	out = mustRun(t, state, "setinterval", feed.Url, "90m")
+	assertContains(t, out, "Blog is now fetched every 1h30m0s")
+	out = mustRun(t, state, "feedinfo", feed.Url)
+	assertContains(t, out, "Override:          1h30m0s", "Polled every:      1h30m0s")
+	out = mustRun(t, state, "setinterval", feed.Url, "auto")
+	assertContains(t, out, "Blog is now fetched on its own schedule")
+	out = mustRun(t, state, "feedinfo", feed.Url)
+	assertContains(t, out, "Override:          none")
+
+	runError(t, state, "error getting feed", "feedinfo", "https://example.com/missing")
+	runError(t, state, "error getting feed", "setinterval", "https://example.com/missing", "1h")
+	runError(t, state, "error parsing duration", "setinterval", feed.Url, "hourly")
+	runError(t, state, "interval must be at least one second", "setinterval", feed.Url, "500ms")
+	runError(t, state, "interval must be at least one second", "setinterval", feed.Url, "0s")
+	runError(t, state, "interval must be at least one second", "setinterval", "--", feed.Url, "-1h")
+	runError(t, state, "interval must be at most 596523h14m7s", "setinterval", feed.Url, "900000h")
+
+	out = mustRun(t, state, "feedhealth")
+	assertContains(t, out, "All feeds are healthy")
+	err := state.DB.RecordFeedFailure(ctx, database.RecordFeedFailureParams{
+		ID: feed.ID,
+		LastError: sql.NullString{String: "unexpected status 503", Valid: true},
+	})
+	if err != nil { t.Fatalf("RecordFeedFailure: %v", err) }
+	out = mustRun(t, state, "--output", "csv", "feedhealth")
+	assertContains(t, out, "Blog,https://example.com/feed,1", "unexpected status 503")
+}
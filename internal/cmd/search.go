@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/output"
+	"strings"
+)
+
+// HandlerSearch runs a full-text search over the posts of the feeds the
+// user follows, or of every feed with --all, best matches first. The
+// query accepts web search syntax: "quoted phrases", OR and -excluded.
+func HandlerSearch(state *State, cmd Command, user *database.User) error {
+	query := strings.TrimSpace(strings.Join(cmd.Arguments, " "))
+	if query == "" {
+		return fmt.Errorf("missing search query")
+	}
+	limit, err := limitFlag(cmd)
+	if err != nil { return err }
+
+	results, err := state.DB.SearchPosts(
+		context.Background(),
+		database.SearchPostsParams{
+			Query: query,
+			AllFeeds: cmd.Bool("all"),
+			UserID: user.ID,
+			Limit: limit,
+		},
+	)
+	if err != nil { return fmt.Errorf("error searching posts: %v", err) }
+	return render(cmd, fmt.Sprintf("No posts match %q", query), []output.Column[database.SearchPostsRow]{
+		{Name: "id", Value: func(result database.SearchPostsRow) any { return result.ID }},
+		{Name: "title", Value: func(result database.SearchPostsRow) any { return result.Title }},
+		{Name: "feed", Value: func(result database.SearchPostsRow) any { return result.FeedName }},
+		{Name: "rank", Value: func(result database.SearchPostsRow) any { return result.Rank }},
+		{Name: "url", Value: func(result database.SearchPostsRow) any { return result.Url }},
+		{Name: "snippet", Value: func(result database.SearchPostsRow) any { return output.Marked(result.Snippet) }},
+	}, results)
+}
+
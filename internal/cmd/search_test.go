@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	seedPosts(t, state, user)
+
+	out := mustRun(t, state, "--output", "csv", "search", "second", "gophers")
+	assertContains(t, out, "Second")
+	if strings.Contains(out, "First") {
+		t.Errorf("search matched a post without every term:\n%s", out)
+	}
+	out = mustRun(t, state, "--output", "csv", "search", "gophers -second")
+	assertContains(t, out, "First", "Third")
+	if strings.Contains(out, "Second") {
+		t.Errorf("search matched an excluded post:\n%s", out)
+	}
+	out = mustRun(t, state, "search", "--limit", "1", "gophers")
+	if strings.Count(out, "gophers") != 1 {
+		t.Errorf("search --limit 1 returned more than one post:\n%s", out)
+	}
+
+	// posts of feeds bob does not follow only show up with --all
+	loginAs(t, state, "bob")
+	out = mustRun(t, state, "search", "gophers")
+	assertContains(t, out, `No posts match "gophers"`)
+	out = mustRun(t, state, "search", "--all", "gophers")
+	assertContains(t, out, "Third")
+
+	runError(t, state, "missing search query", "search", " ")
+	for _, limit := range []string{"0", "-1", "2147483648"} {
+		runError(t, state, "invalid limit: "+limit, "search", "--limit", limit, "gophers")
+	}
+	runError(t, state, "expected at least 1 argument", "search", "--all")
+}
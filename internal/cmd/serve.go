@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gator/internal/api"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const defaultServeAddr = "localhost:8080"
+
+// HandlerServe runs the JSON API until SIGINT or SIGTERM, then lets
+// in-flight requests finish before returning.
+func HandlerServe(state *State, cmd Command) error {
+	addr := defaultServeAddr
+	if len(cmd.Arguments) > 0 {
+		addr = cmd.Arguments[0]
+	}
+	server := &http.Server{
+		Addr: addr,
+		Handler: api.New(state.DB).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	fmt.Printf("Serving the gator API on http://%s/v1/\n", addr)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("error serving API: %v", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if err != nil { return fmt.Errorf("error shutting down API server: %v", err) }
+	err = <-serveErr
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving API: %v", err)
+	}
+	fmt.Printf("API server stopped\n")
+	return nil
+}
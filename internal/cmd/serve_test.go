@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+)
+
+// freeAddr returns a local address nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestServe(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	addFeed(t, state, user, "Blog", "https://example.com/feed")
+	addr := freeAddr(t)
+
+	// the server answers from the store the command was given
+	interruptWhen(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/v1/feeds")
+		if err != nil { return false }
+		defer resp.Body.Close()
+		var feeds []map[string]any
+		err = json.NewDecoder(resp.Body).Decode(&feeds)
+		return err == nil && len(feeds) == 1 && feeds[0]["name"] == "Blog"
+	})
+	out := mustRun(t, state, "serve", addr)
+	assertContains(t, out, "Serving the gator API on http://"+addr+"/v1/", "API server stopped")
+}
+
+func TestServeAddressInUse(t *testing.T) {
+	state := newTestState(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	defer listener.Close()
+
+	runError(t, state, "error serving API", "serve", listener.Addr().String())
+	runError(t, state, "expected at most 1 argument", "serve", "a", "b")
+}
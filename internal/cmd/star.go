@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/output"
+	"strings"
+	"time"
+)
+
+// HandlerStar bookmarks a post, with the rest of the arguments as an
+// optional note. Starring an already starred post replaces its note.
+func HandlerStar(state *State, cmd Command, user *database.User) error {
+	ids, err := parsePostIDs(cmd.Arguments[:1])
+	if err != nil { return err }
+	note := strings.TrimSpace(strings.Join(cmd.Arguments[1:], " "))
+
+	err = state.DB.StarPost(
+		context.Background(),
+		database.StarPostParams{
+			UserID: user.ID,
+			PostID: ids[0],
+			Note: sql.NullString{
+				String: note,
+				Valid: note != "",
+			},
+		},
+	)
+	if err != nil { return fmt.Errorf("error starring post: %v", err) }
+	fmt.Printf("Starred %s\n", ids[0])
+	return nil
+}
+
+
+func HandlerUnstar(state *State, cmd Command, user *database.User) error {
+	ids, err := parsePostIDs(cmd.Arguments)
+	if err != nil { return err }
+	var count int64
+	for _, id := range ids {
+		removed, err := state.DB.UnstarPost(
+			context.Background(),
+			database.UnstarPostParams{
+				UserID: user.ID,
+				PostID: id,
+			},
+		)
+		if err != nil { return fmt.Errorf("error unstarring post %s: %v", id, err) }
+		count += removed
+	}
+	fmt.Printf("Unstarred %d posts\n", count)
+	return nil
+}
+
+
+func HandlerStarred(state *State, cmd Command, user *database.User) error {
+	posts, err := state.DB.GetStarredPostsForUser(context.Background(), user.ID)
+	if err != nil { return fmt.Errorf("error listing starred posts: %v", err) }
+	return render(cmd, "No starred posts", []output.Column[database.GetStarredPostsForUserRow]{
+		{Name: "id", Value: func(post database.GetStarredPostsForUserRow) any { return post.ID }},
+		{Name: "title", Value: func(post database.GetStarredPostsForUserRow) any { return post.Title }},
+		{Name: "feed", Value: func(post database.GetStarredPostsForUserRow) any { return post.FeedName }},
+		{Name: "url", Value: func(post database.GetStarredPostsForUserRow) any { return post.Url }},
+		{Name: "starred_at", Value: func(post database.GetStarredPostsForUserRow) any { return post.StarredAt }},
+		{Name: "note", Value: func(post database.GetStarredPostsForUserRow) any { return output.NullString(post.Note) }},
+	}, posts)
+}
+
+
+// HandlerPrune deletes posts published before a date, or older than a
+// duration. Starred posts are always kept. The cutoff must lie in the
+// past, so a mistyped age cannot wipe every post.
+func HandlerPrune(state *State, cmd Command) error {
+	var before time.Time
+	if age, err := time.ParseDuration(cmd.Arguments[0]); err == nil {
+		if age <= 0 { return fmt.Errorf("invalid age %s, must be positive", cmd.Arguments[0]) }
+		before = time.Now().Add(-age)
+	} else {
+		before, err = parseDateArg(cmd.Arguments[0])
+		if err != nil { return err }
+		if before.After(time.Now()) { return fmt.Errorf("invalid date %s, must not be in the future", cmd.Arguments[0]) }
+	}
+
+	count, err := state.DB.DeletePostsOlderThan(context.Background(), before)
+	if err != nil { return fmt.Errorf("error pruning posts: %v", err) }
+	fmt.Printf("Deleted %d posts published before %s\n", count, before.Format(time.RFC1123))
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStar(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	_, posts := seedPosts(t, state, user)
+
+	out := mustRun(t, state, "starred")
+	assertContains(t, out, "No starred posts")
+
+	out = mustRun(t, state, "star", posts[1].ID.String(), "read", "later")
+	assertContains(t, out, "Starred "+posts[1].ID.String())
+	out = mustRun(t, state, "--output", "csv", "starred")
+	assertContains(t, out, "Second", "read later")
+
+	// starring again replaces the note
+	mustRun(t, state, "star", posts[1].ID.String())
+	out = mustRun(t, state, "--output", "csv", "starred")
+	if strings.Contains(out, "read later") {
+		t.Errorf("note survived starring again:\n%s", out)
+	}
+
+	runError(t, state, `invalid post id "42"`, "star", "42")
+	runError(t, state, "error starring post", "star", uuid.NewString())
+
+	out = mustRun(t, state, "unstar", posts[1].ID.String(), posts[2].ID.String())
+	assertContains(t, out, "Unstarred 1 posts")
+	out = mustRun(t, state, "starred")
+	assertContains(t, out, "No starred posts")
+	runError(t, state, `invalid post id "42"`, "unstar", "42")
+}
+
+func TestPrune(t *testing.T) {
+	state := newTestState(t)
+	user := loginAs(t, state, "alice")
+	_, posts := seedPosts(t, state, user)
+	mustRun(t, state, "star", posts[0].ID.String())
+
+	// the starred post is kept
+	out := mustRun(t, state, "prune", "2026-01-03")
+	assertContains(t, out, "Deleted 1 posts published before Sat, 03 Jan 2026 00:00:00 UTC")
+	read := readTitles(t, state, user)
+	if _, ok := read["Second"]; ok || len(read) != 2 {
+		t.Errorf("posts after pruning = %v, want First and Third", read)
+	}
+
+	// posts published within the duration are kept
+	feed, err := state.DB.GetFeed(context.Background(), "https://example.com/feed")
+	if err != nil { t.Fatal(err) }
+	addPost(t, state, feed, database.UpsertPostParams{
+		Title: "Fresh",
+		Url: "https://example.com/fresh",
+		PublishedAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	out = mustRun(t, state, "prune", "24h")
+	assertContains(t, out, "Deleted 1 posts published before")
+	if read := readTitles(t, state, user); len(read) != 2 {
+		t.Errorf("posts after pruning = %v, want First and Fresh", read)
+	}
+
+	runError(t, state, "invalid date", "prune", "last week")
+	for _, age := range []string{"0", "0s", "-1h"} {
+		runError(t, state, "must be positive", "prune", "--", age)
+	}
+	runError(t, state, "must not be in the future", "prune", time.Now().AddDate(0, 0, 2).Format(time.DateOnly))
+	if read := readTitles(t, state, user); len(read) != 2 {
+		t.Errorf("posts after rejected prunes = %v, want First and Fresh", read)
+	}
+}
@@ -6,12 +6,21 @@ import (
 	"os"
 	"fmt"
 	"path/filepath"
+	"time"
+)
+
+
+const (
+	defaultPollMinInterval = 15 * time.Minute
+	defaultPollMaxInterval = 24 * time.Hour
 )
 
 
 type Config struct {
 	DBUrl string `json:"db_url"`
 	CurrentUser string `json:"current_user_name"`
+	PollMinInterval string `json:"poll_min_interval,omitempty"`
+	PollMaxInterval string `json:"poll_max_interval,omitempty"`
 }
 
 
@@ -44,3 +53,23 @@ func (c *Config) SetUser(user string) error {
 
 	return nil
 }
+
+
+// PollBounds returns the range adaptive polling intervals are clamped to,
+// from poll_min_interval and poll_max_interval or their defaults.
+func (c *Config) PollBounds() (time.Duration, time.Duration, error) {
+	minInterval, maxInterval := defaultPollMinInterval, defaultPollMaxInterval
+	var err error
+	if c.PollMinInterval != "" {
+		minInterval, err = time.ParseDuration(c.PollMinInterval)
+		if err != nil { return 0, 0, fmt.Errorf("error parsing poll_min_interval: %v", err) }
+	}
+	if c.PollMaxInterval != "" {
+		maxInterval, err = time.ParseDuration(c.PollMaxInterval)
+		if err != nil { return 0, 0, fmt.Errorf("error parsing poll_max_interval: %v", err) }
+	}
+	if minInterval > maxInterval {
+		return 0, 0, fmt.Errorf("poll_min_interval %s is longer than poll_max_interval %s", minInterval, maxInterval)
+	}
+	return minInterval, maxInterval, nil
+}
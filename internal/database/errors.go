@@ -0,0 +1,21 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrUniqueViolation is returned by Querier implementations other than
+// PostgreSQL when an insert conflicts with a unique constraint.
+var ErrUniqueViolation = errors.New("duplicate key value violates unique constraint")
+
+// IsUniqueViolation reports whether err comes from an insert that
+// conflicts with a unique constraint, whichever store returned it.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return errors.Is(err, ErrUniqueViolation)
+}
@@ -7,11 +7,55 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const claimNextFeedToFetch = `-- name: ClaimNextFeedToFetch :one
+UPDATE feeds
+SET last_feteched = now(),
+    next_fetch_at = now() + make_interval(secs => $1::integer),
+    updated_at = now()
+WHERE feeds.id = (
+    SELECT id
+    FROM feeds
+    WHERE next_fetch_at IS NULL OR next_fetch_at <= now()
+    ORDER BY last_feteched NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, name, url, user_id, last_feteched, etag, last_modified, last_error, consecutive_failures, last_success_at, next_fetch_at, hinted_interval_seconds, interval_override_seconds, skip_hours, skip_days, adaptive_interval_seconds, site_url
+`
+
+func (q *Queries) ClaimNextFeedToFetch(ctx context.Context, leaseSeconds int32) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimNextFeedToFetch, leaseSeconds)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFeteched,
+		&i.Etag,
+		&i.LastModified,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.HintedIntervalSeconds,
+		&i.IntervalOverrideSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.AdaptiveIntervalSeconds,
+		&i.SiteUrl,
+	)
+	return i, err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (name, url, user_id)
 VALUES (
@@ -19,7 +63,7 @@ VALUES (
     $2,
     $3
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_feteched
+RETURNING id, created_at, updated_at, name, url, user_id, last_feteched, etag, last_modified, last_error, consecutive_failures, last_success_at, next_fetch_at, hinted_interval_seconds, interval_override_seconds, skip_hours, skip_days, adaptive_interval_seconds, site_url
 `
 
 type CreateFeedParams struct {
@@ -39,6 +83,18 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFeteched,
+		&i.Etag,
+		&i.LastModified,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.HintedIntervalSeconds,
+		&i.IntervalOverrideSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.AdaptiveIntervalSeconds,
+		&i.SiteUrl,
 	)
 	return i, err
 }
@@ -50,10 +106,10 @@ WITH inserted_feed_follow AS (
         $1,
         $2
     )
-    RETURNING id, created_at, updated_at, user_id, feed_id
+    RETURNING id, created_at, updated_at, user_id, feed_id, folder
 )
 SELECT
-    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id,
+    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id, inserted_feed_follow.folder,
     users.name as user_name,
     feeds.name as feed_name
 FROM inserted_feed_follow
@@ -72,6 +128,7 @@ type CreateFeedFollowRow struct {
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	Folder    sql.NullString
 	UserName  string
 	FeedName  string
 }
@@ -85,14 +142,73 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.Folder,
 		&i.UserName,
 		&i.FeedName,
 	)
 	return i, err
 }
 
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE feeds.id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const getFailingFeeds = `-- name: GetFailingFeeds :many
+SELECT id, created_at, updated_at, name, url, user_id, last_feteched, etag, last_modified, last_error, consecutive_failures, last_success_at, next_fetch_at, hinted_interval_seconds, interval_override_seconds, skip_hours, skip_days, adaptive_interval_seconds, site_url FROM feeds
+WHERE consecutive_failures > 0
+ORDER BY consecutive_failures DESC, name
+`
+
+func (q *Queries) GetFailingFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFailingFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFeteched,
+			&i.Etag,
+			&i.LastModified,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.LastSuccessAt,
+			&i.NextFetchAt,
+			&i.HintedIntervalSeconds,
+			&i.IntervalOverrideSeconds,
+			&i.SkipHours,
+			&i.SkipDays,
+			&i.AdaptiveIntervalSeconds,
+			&i.SiteUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFeed = `-- name: GetFeed :one
-SELECT id, created_at, updated_at, name, url, user_id, last_feteched FROM feeds where feeds.url = $1
+SELECT id, created_at, updated_at, name, url, user_id, last_feteched, etag, last_modified, last_error, consecutive_failures, last_success_at, next_fetch_at, hinted_interval_seconds, interval_override_seconds, skip_hours, skip_days, adaptive_interval_seconds, site_url FROM feeds where feeds.url = $1
 `
 
 func (q *Queries) GetFeed(ctx context.Context, url string) (Feed, error) {
@@ -106,6 +222,18 @@ func (q *Queries) GetFeed(ctx context.Context, url string) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFeteched,
+		&i.Etag,
+		&i.LastModified,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.HintedIntervalSeconds,
+		&i.IntervalOverrideSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.AdaptiveIntervalSeconds,
+		&i.SiteUrl,
 	)
 	return i, err
 }
@@ -113,7 +241,15 @@ func (q *Queries) GetFeed(ctx context.Context, url string) (Feed, error) {
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT 
     users.name as user_name,
-    feeds.name as feed_name
+    feeds.name as feed_name,
+    feeds.url as feed_url,
+    (
+        SELECT count(*)
+        FROM posts
+        LEFT JOIN post_states on post_states.post_id = posts.id
+            AND post_states.user_id = feed_follows.user_id
+        WHERE posts.feed_id = feeds.id AND post_states.read IS NOT TRUE
+    ) as unread_count
 FROM feed_follows
 JOIN users on feed_follows.user_id = users.id
 JOIN feeds on feed_follows.feed_id = feeds.id
@@ -121,8 +257,10 @@ WHERE feed_follows.user_id = $1
 `
 
 type GetFeedFollowsForUserRow struct {
-	UserName string
-	FeedName string
+	UserName    string
+	FeedName    string
+	FeedUrl     string
+	UnreadCount int64
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
@@ -134,7 +272,12 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	var items []GetFeedFollowsForUserRow
 	for rows.Next() {
 		var i GetFeedFollowsForUserRow
-		if err := rows.Scan(&i.UserName, &i.FeedName); err != nil {
+		if err := rows.Scan(
+			&i.UserName,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.UnreadCount,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -186,37 +329,155 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT 
-    id, created_at, updated_at, name, url, user_id, last_feteched
-FROM feeds
-ORDER by last_feteched NULLS FIRST
-LIMIT 1
+const getFollowedFeedsForUser = `-- name: GetFollowedFeedsForUser :many
+SELECT
+    feeds.name,
+    feeds.url,
+    feeds.site_url,
+    feed_follows.folder
+FROM feed_follows
+JOIN feeds on feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feed_follows.folder NULLS FIRST, feeds.name
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-		&i.LastFeteched,
-	)
-	return i, err
+type GetFollowedFeedsForUserRow struct {
+	Name    string
+	Url     string
+	SiteUrl sql.NullString
+	Folder  sql.NullString
+}
+
+func (q *Queries) GetFollowedFeedsForUser(ctx context.Context, userID uuid.UUID) ([]GetFollowedFeedsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedFeedsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowedFeedsForUserRow
+	for rows.Next() {
+		var i GetFollowedFeedsForUserRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Url,
+			&i.SiteUrl,
+			&i.Folder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordFeedFailure = `-- name: RecordFeedFailure :exec
+UPDATE feeds
+SET last_error = $2,
+    consecutive_failures = consecutive_failures + 1,
+    next_fetch_at = $3,
+    updated_at = now()
+WHERE feeds.id = $1
+`
+
+type RecordFeedFailureParams struct {
+	ID          uuid.UUID
+	LastError   sql.NullString
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) RecordFeedFailure(ctx context.Context, arg RecordFeedFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedFailure, arg.ID, arg.LastError, arg.NextFetchAt)
+	return err
+}
+
+const recordFeedSuccess = `-- name: RecordFeedSuccess :exec
+UPDATE feeds
+SET last_error = NULL,
+    consecutive_failures = 0,
+    last_success_at = now(),
+    next_fetch_at = $2,
+    updated_at = now()
+WHERE feeds.id = $1
+`
+
+type RecordFeedSuccessParams struct {
+	ID          uuid.UUID
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, arg RecordFeedSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedSuccess, arg.ID, arg.NextFetchAt)
+	return err
+}
+
+const setFeedAdaptiveInterval = `-- name: SetFeedAdaptiveInterval :exec
+UPDATE feeds
+SET adaptive_interval_seconds = $2, updated_at = now()
+WHERE feeds.id = $1
+`
+
+type SetFeedAdaptiveIntervalParams struct {
+	ID                      uuid.UUID
+	AdaptiveIntervalSeconds sql.NullInt32
+}
+
+func (q *Queries) SetFeedAdaptiveInterval(ctx context.Context, arg SetFeedAdaptiveIntervalParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedAdaptiveInterval, arg.ID, arg.AdaptiveIntervalSeconds)
+	return err
+}
+
+const setFeedFollowFolder = `-- name: SetFeedFollowFolder :exec
+UPDATE feed_follows
+SET folder = $3, updated_at = now()
+WHERE feed_follows.user_id = $1 AND feed_follows.feed_id = $2
+`
+
+type SetFeedFollowFolderParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+	Folder sql.NullString
+}
+
+func (q *Queries) SetFeedFollowFolder(ctx context.Context, arg SetFeedFollowFolderParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFollowFolder, arg.UserID, arg.FeedID, arg.Folder)
+	return err
 }
 
-const markFeedFetched = `-- name: MarkFeedFetched :exec
+const setFeedIntervalOverride = `-- name: SetFeedIntervalOverride :exec
 UPDATE feeds
-SET last_feteched = now(), updated_at = now()
+SET interval_override_seconds = $2, updated_at = now()
 WHERE feeds.id = $1
 `
 
-func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+type SetFeedIntervalOverrideParams struct {
+	ID                      uuid.UUID
+	IntervalOverrideSeconds sql.NullInt32
+}
+
+func (q *Queries) SetFeedIntervalOverride(ctx context.Context, arg SetFeedIntervalOverrideParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedIntervalOverride, arg.ID, arg.IntervalOverrideSeconds)
+	return err
+}
+
+const setFeedSiteURL = `-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET site_url = $2, updated_at = now()
+WHERE feeds.id = $1
+`
+
+type SetFeedSiteURLParams struct {
+	ID      uuid.UUID
+	SiteUrl sql.NullString
+}
+
+func (q *Queries) SetFeedSiteURL(ctx context.Context, arg SetFeedSiteURLParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedSiteURL, arg.ID, arg.SiteUrl)
 	return err
 }
 
@@ -234,3 +495,46 @@ func (q *Queries) UnfollowFeed(ctx context.Context, arg UnfollowFeedParams) erro
 	_, err := q.db.ExecContext(ctx, unfollowFeed, arg.UserID, arg.FeedID)
 	return err
 }
+
+const updateFeedCache = `-- name: UpdateFeedCache :exec
+UPDATE feeds
+SET etag = $2, last_modified = $3, updated_at = now()
+WHERE feeds.id = $1
+`
+
+type UpdateFeedCacheParams struct {
+	ID           uuid.UUID
+	Etag         sql.NullString
+	LastModified sql.NullString
+}
+
+const updateFeedSchedule = `-- name: UpdateFeedSchedule :exec
+UPDATE feeds
+SET hinted_interval_seconds = $2,
+    skip_hours = $3,
+    skip_days = $4,
+    updated_at = now()
+WHERE feeds.id = $1
+`
+
+type UpdateFeedScheduleParams struct {
+	ID                    uuid.UUID
+	HintedIntervalSeconds sql.NullInt32
+	SkipHours             int32
+	SkipDays              int32
+}
+
+func (q *Queries) UpdateFeedSchedule(ctx context.Context, arg UpdateFeedScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedSchedule,
+		arg.ID,
+		arg.HintedIntervalSeconds,
+		arg.SkipHours,
+		arg.SkipDays,
+	)
+	return err
+}
+
+func (q *Queries) UpdateFeedCache(ctx context.Context, arg UpdateFeedCacheParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCache, arg.ID, arg.Etag, arg.LastModified)
+	return err
+}
@@ -0,0 +1,13 @@
+package database
+
+import "time"
+
+// FeedClaimLease is how long ClaimNextFeedToFetch pushes a claimed feed's
+// next_fetch_at out, so no other worker takes it while it is fetched. The
+// outcome recorded afterwards replaces the lease; a worker that dies keeps
+// the feed out of rotation until the lease runs out. Every backend takes
+// the lease from the caller, so this is the only place it is set.
+const FeedClaimLease = 10 * time.Minute
+
+// FeedClaimLeaseSeconds is FeedClaimLease as ClaimNextFeedToFetch takes it.
+const FeedClaimLeaseSeconds = int32(FeedClaimLease / time.Second)
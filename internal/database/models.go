@@ -11,14 +11,39 @@ import (
 	"github.com/google/uuid"
 )
 
+type Enclosure struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PostID          uuid.UUID
+	Url             string
+	MimeType        sql.NullString
+	Length          sql.NullInt64
+	DurationSeconds sql.NullInt32
+	Episode         sql.NullInt32
+	ImageUrl        sql.NullString
+}
+
 type Feed struct {
-	ID           uuid.UUID
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	Name         string
-	Url          string
-	UserID       uuid.UUID
-	LastFeteched sql.NullTime
+	ID                      uuid.UUID
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+	Name                    string
+	Url                     string
+	UserID                  uuid.UUID
+	LastFeteched            sql.NullTime
+	Etag                    sql.NullString
+	LastModified            sql.NullString
+	LastError               sql.NullString
+	ConsecutiveFailures     int32
+	LastSuccessAt           sql.NullTime
+	NextFetchAt             sql.NullTime
+	HintedIntervalSeconds   sql.NullInt32
+	IntervalOverrideSeconds sql.NullInt32
+	SkipHours               int32
+	SkipDays                int32
+	AdaptiveIntervalSeconds sql.NullInt32
+	SiteUrl                 sql.NullString
 }
 
 type FeedFollow struct {
@@ -27,6 +52,7 @@ type FeedFollow struct {
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	Folder    sql.NullString
 }
 
 type Post struct {
@@ -38,6 +64,25 @@ type Post struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      uuid.UUID
+	Guid        string
+	Content     sql.NullString
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Note      sql.NullString
+}
+
+type PostState struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Read      bool
+	ReadAt    sql.NullTime
 }
 
 type User struct {
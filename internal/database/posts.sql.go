@@ -13,63 +13,104 @@ import (
 	"github.com/google/uuid"
 )
 
-const createPost = `-- name: CreatePost :one
-INSERT INTO posts (title, url, description, published_at, feed_id)
-VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5
-)
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+const adoptLegacyPost = `-- name: AdoptLegacyPost :exec
+UPDATE posts
+SET guid = $1
+WHERE posts.feed_id = $2
+    AND posts.url = $3
+    AND posts.guid = posts.url
+    AND NOT EXISTS (
+        SELECT 1 FROM posts existing
+        WHERE existing.feed_id = $2 AND existing.guid = $1
+    )
 `
 
-type CreatePostParams struct {
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+type AdoptLegacyPostParams struct {
+	Guid   string
+	FeedID uuid.UUID
+	Url    string
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
-	row := q.db.QueryRowContext(ctx, createPost,
-		arg.Title,
-		arg.Url,
-		arg.Description,
-		arg.PublishedAt,
-		arg.FeedID,
-	)
-	var i Post
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Title,
-		&i.Url,
-		&i.Description,
-		&i.PublishedAt,
-		&i.FeedID,
-	)
-	return i, err
+// AdoptLegacyPost gives the post stored under its url before posts had
+// guids the item's guid, unless a post already has that guid.
+func (q *Queries) AdoptLegacyPost(ctx context.Context, arg AdoptLegacyPostParams) error {
+	_, err := q.db.ExecContext(ctx, adoptLegacyPost, arg.Guid, arg.FeedID, arg.Url)
+	return err
+}
+
+const deletePostsOlderThan = `-- name: DeletePostsOlderThan :execrows
+DELETE FROM posts
+WHERE COALESCE(posts.published_at, posts.created_at) < $1::timestamp
+    AND NOT EXISTS (
+        SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id
+    )
+`
+
+func (q *Queries) DeletePostsOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePostsOlderThan, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFeedPublishDates = `-- name: GetFeedPublishDates :many
+SELECT published_at
+FROM posts
+WHERE feed_id = $1 AND published_at IS NOT NULL
+ORDER BY published_at DESC
+LIMIT $2
+`
+
+type GetFeedPublishDatesParams struct {
+	FeedID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetFeedPublishDates(ctx context.Context, arg GetFeedPublishDatesParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedPublishDates, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var published_at sql.NullTime
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
 SELECT 
-    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id,
-    feeds.name as feed_name
+    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.content,
+    feeds.name as feed_name,
+    feeds.url as feed_url,
+    COALESCE(post_states.read, false)::boolean as read
 FROM posts
 INNER JOIN feeds on posts.feed_id = feeds.id
 INNER JOIN feed_follows on feed_follows.feed_id = feeds.id
+LEFT JOIN post_states on post_states.post_id = posts.id
+    AND post_states.user_id = feed_follows.user_id
 WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC
-LIMIT $2
+    AND (NOT $2::boolean OR post_states.read IS NOT TRUE)
+ORDER BY COALESCE(posts.published_at, posts.created_at) DESC
+LIMIT $3
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Limit      int32
 }
 
 type GetPostsForUserRow struct {
@@ -81,11 +122,15 @@ type GetPostsForUserRow struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      uuid.UUID
+	Guid        string
+	Content     sql.NullString
 	FeedName    string
+	FeedUrl     string
+	Read        bool
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.UnreadOnly, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +147,94 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Guid,
+			&i.Content,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.published_at,
+    feeds.name as feed_name,
+    ts_rank(post_search_vector(posts.title, posts.description), query)::real as rank,
+    ts_headline(
+        'english',
+        posts.title || ' ' || coalesce(posts.description, ''),
+        query,
+        'StartSel=**, StopSel=**, MinWords=15, MaxWords=35'
+    )::text as snippet
+FROM posts
+INNER JOIN feeds on posts.feed_id = feeds.id,
+    websearch_to_tsquery('english', $1::text) query
+WHERE post_search_vector(posts.title, posts.description) @@ query
+    AND (
+        $2::boolean
+        OR EXISTS (
+            SELECT 1 FROM feed_follows
+            WHERE feed_follows.feed_id = posts.feed_id
+                AND feed_follows.user_id = $3
+        )
+    )
+ORDER BY rank DESC, COALESCE(posts.published_at, posts.created_at) DESC
+LIMIT $4
+`
+
+type SearchPostsParams struct {
+	Query    string
+	AllFeeds bool
+	UserID   uuid.UUID
+	Limit    int32
+}
+
+type SearchPostsRow struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	PublishedAt sql.NullTime
+	FeedName    string
+	Rank        float32
+	Snippet     string
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.AllFeeds,
+		arg.UserID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
 			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -116,3 +248,65 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const upsertPost = `-- name: UpsertPost :one
+INSERT INTO posts (title, url, description, published_at, feed_id, guid, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (feed_id, guid) DO UPDATE
+SET title = excluded.title,
+    url = excluded.url,
+    description = excluded.description,
+    content = excluded.content,
+    published_at = COALESCE(excluded.published_at, posts.published_at),
+    updated_at = now()
+WHERE posts.title IS DISTINCT FROM excluded.title
+    OR posts.url IS DISTINCT FROM excluded.url
+    OR posts.description IS DISTINCT FROM excluded.description
+    OR posts.content IS DISTINCT FROM excluded.content
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content
+`
+
+type UpsertPostParams struct {
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	FeedID      uuid.UUID
+	Guid        string
+	Content     sql.NullString
+}
+
+// UpsertPost returns sql.ErrNoRows when the post is already stored unchanged.
+func (q *Queries) UpsertPost(ctx context.Context, arg UpsertPostParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, upsertPost,
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		arg.PublishedAt,
+		arg.FeedID,
+		arg.Guid,
+		arg.Content,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+	)
+	return i, err
+}
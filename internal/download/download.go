@@ -0,0 +1,139 @@
+// Package download saves enclosures to disk. Data goes to a ".part" file
+// next to the destination, which is renamed once complete, so an
+// interrupted download is resumed with a Range request on the next try.
+// The ETag or Last-Modified of the response that started the part file is
+// kept beside it and sent as If-Range, so a file that changed on the
+// server is downloaded again in full rather than spliced onto old bytes.
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Result describes a finished download.
+type Result struct {
+	// Resumed is where a previous partial download left off, zero when
+	// the download started from scratch.
+	Resumed int64
+	// Size is the size of the complete file.
+	Size int64
+}
+
+// Fetch downloads url to path, resuming path.part if it exists.
+func Fetch(ctx context.Context, url, path string) (Result, error) {
+	partPath := path + ".part"
+	validatorPath := partPath + ".validator"
+	var offset int64
+	validator, _ := os.ReadFile(validatorPath)
+	// without a validator there is no telling whether the part file still
+	// holds the start of the same file, so it is downloaded again
+	if info, err := os.Stat(partPath); err == nil && len(validator) > 0 {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil { return Result{}, fmt.Errorf("error downloading %s: %v", url, err) }
+	req.Header.Set("User-Agent", "gator")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", string(validator))
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil { return Result{}, fmt.Errorf("error downloading %s: %v", url, err) }
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, _, err := contentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			return Result{}, fmt.Errorf("error resuming %s: server sent an unexpected range %q", url, resp.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// a fresh download, or the file changed or the server ignored the
+		// range, so start over
+		offset = 0
+		flags |= os.O_TRUNC
+		err = saveValidator(validatorPath, resp.Header)
+		if err != nil { return Result{}, err }
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the part file may already hold the whole enclosure
+		_, size, err := contentRange(resp.Header.Get("Content-Range"))
+		if err != nil || size != offset {
+			return Result{}, fmt.Errorf("error resuming %s: server rejected the range; delete %s to start over", url, partPath)
+		}
+		return Result{Resumed: offset, Size: offset}, finish(partPath, validatorPath, path)
+	default:
+		return Result{}, fmt.Errorf("error downloading %s: unexpected status %s", url, resp.Status)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil { return Result{}, fmt.Errorf("error writing %s: %v", partPath, err) }
+	written, err := io.Copy(file, resp.Body)
+	closeErr := file.Close()
+	if err != nil { return Result{}, fmt.Errorf("error downloading %s after %d bytes, run again to resume: %v", url, offset+written, err) }
+	if closeErr != nil { return Result{}, fmt.Errorf("error writing %s: %v", partPath, closeErr) }
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return Result{}, fmt.Errorf("error downloading %s: got %d of %d bytes, run again to resume", url, written, resp.ContentLength)
+	}
+	return Result{Resumed: offset, Size: offset + written}, finish(partPath, validatorPath, path)
+}
+
+
+// saveValidator keeps what identifies this version of the file for
+// If-Range: a strong ETag, or else Last-Modified. Weak ETags cannot be
+// used for ranges. With neither, a later attempt starts over.
+func saveValidator(validatorPath string, header http.Header) error {
+	validator := header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		err := os.Remove(validatorPath)
+		if err != nil && !os.IsNotExist(err) { return fmt.Errorf("error removing %s: %v", validatorPath, err) }
+		return nil
+	}
+	err := os.WriteFile(validatorPath, []byte(validator), 0o644)
+	if err != nil { return fmt.Errorf("error writing %s: %v", validatorPath, err) }
+	return nil
+}
+
+
+func finish(partPath, validatorPath, path string) error {
+	err := os.Rename(partPath, path)
+	if err != nil { return fmt.Errorf("error saving %s: %v", path, err) }
+	os.Remove(validatorPath)
+	return nil
+}
+
+
+// contentRange parses "bytes start-end/size" and "bytes */size". The size
+// is -1 when the server gives "*".
+func contentRange(value string) (start, size int64, err error) {
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok { return 0, 0, fmt.Errorf("invalid Content-Range %q", value) }
+	span, total, ok := strings.Cut(spec, "/")
+	if !ok { return 0, 0, fmt.Errorf("invalid Content-Range %q", value) }
+
+	size = -1
+	if total != "*" {
+		size, err = strconv.ParseInt(total, 10, 64)
+		if err != nil { return 0, 0, fmt.Errorf("invalid Content-Range %q", value) }
+	}
+	if span == "*" {
+		return 0, size, nil
+	}
+	first, _, ok := strings.Cut(span, "-")
+	if !ok { return 0, 0, fmt.Errorf("invalid Content-Range %q", value) }
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil { return 0, 0, fmt.Errorf("invalid Content-Range %q", value) }
+	return start, size, nil
+}
@@ -0,0 +1,165 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// server serves content with http.ServeContent, which honours Range and
+// If-Range, under the given ETag. It counts requests carrying a Range.
+type server struct {
+	content []byte
+	etag string
+	ranged int
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Range") != "" {
+		s.ranged++
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	http.ServeContent(w, r, "episode.mp3", time.Time{}, bytes.NewReader(s.content))
+}
+
+func fetch(t *testing.T, url, path string) Result {
+	t.Helper()
+	result, err := Fetch(context.Background(), url, path)
+	if err != nil { t.Fatalf("Fetch: %v", err) }
+	return result
+}
+
+func assertFile(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil { t.Fatalf("reading %s: %v", path, err) }
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s holds %d bytes that differ from the %d served", path, len(got), len(want))
+	}
+	for _, leftover := range []string{path + ".part", path + ".part.validator"} {
+		if _, err := os.Stat(leftover); err == nil {
+			t.Errorf("%s was left behind", leftover)
+		}
+	}
+}
+
+func TestFetchResumesPartialDownload(t *testing.T) {
+	srv := &server{content: bytes.Repeat([]byte("0123456789"), 1000), etag: `"v1"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+
+	os.WriteFile(path+".part", srv.content[:1234], 0o644)
+	os.WriteFile(path+".part.validator", []byte(`"v1"`), 0o644)
+
+	result := fetch(t, ts.URL, path)
+	if result.Resumed != 1234 || result.Size != int64(len(srv.content)) {
+		t.Errorf("result = %+v, want resumed at 1234 of %d", result, len(srv.content))
+	}
+	assertFile(t, path, srv.content)
+}
+
+func TestFetchRestartsWhenFileChanged(t *testing.T) {
+	srv := &server{content: bytes.Repeat([]byte("new!"), 1000), etag: `"v2"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+
+	os.WriteFile(path+".part", bytes.Repeat([]byte("old"), 100), 0o644)
+	os.WriteFile(path+".part.validator", []byte(`"v1"`), 0o644)
+
+	result := fetch(t, ts.URL, path)
+	if result.Resumed != 0 {
+		t.Errorf("resumed at %d from a part file of another version", result.Resumed)
+	}
+	assertFile(t, path, srv.content)
+}
+
+func TestFetchRestartsWithoutValidator(t *testing.T) {
+	srv := &server{content: bytes.Repeat([]byte("abc"), 1000)}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+
+	os.WriteFile(path+".part", []byte("stale"), 0o644)
+
+	fetch(t, ts.URL, path)
+	if srv.ranged != 0 {
+		t.Errorf("sent %d range requests without a validator", srv.ranged)
+	}
+	assertFile(t, path, srv.content)
+}
+
+func TestFetchKeepsValidatorOfInterruptedDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("xyz"), 1000)
+	cut := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if cut {
+			// promise the whole file, then stop halfway
+			cut = false
+			w.Header().Set("Content-Length", "3000")
+			w.Write(content[:1500])
+			return
+		}
+		http.ServeContent(w, r, "episode.mp3", time.Time{}, bytes.NewReader(content))
+	}))
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+
+	_, err := Fetch(context.Background(), ts.URL, path)
+	if err == nil { t.Fatal("Fetch of a truncated response succeeded") }
+	validator, _ := os.ReadFile(path + ".part.validator")
+	if string(validator) != `"v1"` {
+		t.Fatalf("validator = %q, want the ETag", validator)
+	}
+
+	result := fetch(t, ts.URL, path)
+	if result.Resumed != 1500 {
+		t.Errorf("resumed at %d, want 1500", result.Resumed)
+	}
+	assertFile(t, path, content)
+}
+
+func TestFetchCompletePartFile(t *testing.T) {
+	srv := &server{content: []byte("complete"), etag: `"v1"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+
+	os.WriteFile(path+".part", srv.content, 0o644)
+	os.WriteFile(path+".part.validator", []byte(`"v1"`), 0o644)
+
+	result := fetch(t, ts.URL, path)
+	if result.Size != int64(len(srv.content)) {
+		t.Errorf("size = %d, want %d", result.Size, len(srv.content))
+	}
+	assertFile(t, path, srv.content)
+}
+
+func TestContentRange(t *testing.T) {
+	tests := []struct {
+		value string
+		start, size int64
+		ok bool
+	}{
+		{"bytes 100-199/200", 100, 200, true},
+		{"bytes 0-9/*", 0, -1, true},
+		{"bytes */500", 0, 500, true},
+		{"items 0-9/10", 0, 0, false},
+		{"bytes 0-9", 0, 0, false},
+	}
+	for _, test := range tests {
+		start, size, err := contentRange(test.value)
+		if (err == nil) != test.ok || start != test.start || size != test.size {
+			t.Errorf("contentRange(%q) = %d, %d, %v", test.value, start, size, err)
+		}
+	}
+}
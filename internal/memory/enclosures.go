@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"gator/internal/database"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// UpsertEnclosure attaches the enclosure to the post with the given feed
+// and guid, doing nothing when there is no such post, like the SQL
+// INSERT ... SELECT.
+func (s *Store) UpsertEnclosure(ctx context.Context, arg database.UpsertEnclosureParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var post *database.Post
+	for i := range s.posts {
+		if s.posts[i].FeedID == arg.FeedID && s.posts[i].Guid == arg.Guid {
+			post = &s.posts[i]
+			break
+		}
+	}
+	if post == nil {
+		return nil
+	}
+
+	now := s.now()
+	for i, enclosure := range s.enclosures {
+		if enclosure.PostID != post.ID || enclosure.Url != arg.Url {
+			continue
+		}
+		if enclosure.MimeType == arg.MimeType && enclosure.Length == arg.Length &&
+			enclosure.DurationSeconds == arg.DurationSeconds && enclosure.Episode == arg.Episode &&
+			enclosure.ImageUrl == arg.ImageUrl {
+			return nil
+		}
+		enclosure.MimeType = arg.MimeType
+		enclosure.Length = arg.Length
+		enclosure.DurationSeconds = arg.DurationSeconds
+		enclosure.Episode = arg.Episode
+		enclosure.ImageUrl = arg.ImageUrl
+		enclosure.UpdatedAt = now
+		s.enclosures[i] = enclosure
+		return nil
+	}
+	s.enclosures = append(s.enclosures, database.Enclosure{
+		ID: uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		PostID: post.ID,
+		Url: arg.Url,
+		MimeType: arg.MimeType,
+		Length: arg.Length,
+		DurationSeconds: arg.DurationSeconds,
+		Episode: arg.Episode,
+		ImageUrl: arg.ImageUrl,
+	})
+	return nil
+}
+
+
+func (s *Store) GetEnclosuresForPost(ctx context.Context, postID uuid.UUID) ([]database.Enclosure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var enclosures []database.Enclosure
+	for _, enclosure := range s.enclosures {
+		if enclosure.PostID == postID {
+			enclosures = append(enclosures, enclosure)
+		}
+	}
+	sort.SliceStable(enclosures, func(i, j int) bool {
+		a, b := enclosures[i], enclosures[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.Url < b.Url
+	})
+	return enclosures, nil
+}
+
+
+// GetPodcastEpisodesForUser lists audio and video enclosures, and those
+// of unknown type, newest post first.
+func (s *Store) GetPodcastEpisodesForUser(ctx context.Context, arg database.GetPodcastEpisodesForUserParams) ([]database.GetPodcastEpisodesForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetPodcastEpisodesForUserRow
+	for _, enclosure := range s.enclosures {
+		mimeType := enclosure.MimeType.String
+		if enclosure.MimeType.Valid && !strings.HasPrefix(mimeType, "audio/") && !strings.HasPrefix(mimeType, "video/") {
+			continue
+		}
+		post, _ := s.postByID(enclosure.PostID)
+		if !s.isFollowing(arg.UserID, post.FeedID) {
+			continue
+		}
+		read := s.isRead(arg.UserID, post.ID)
+		if arg.UnreadOnly && read {
+			continue
+		}
+		feed, _ := s.feedByID(post.FeedID)
+		rows = append(rows, database.GetPodcastEpisodesForUserRow{
+			PostID: post.ID,
+			Title: post.Title,
+			PublishedAt: post.PublishedAt,
+			FeedName: feed.Name,
+			Url: enclosure.Url,
+			MimeType: enclosure.MimeType,
+			Length: enclosure.Length,
+			DurationSeconds: enclosure.DurationSeconds,
+			Episode: enclosure.Episode,
+			Read: read,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return s.newerFirst(rows[i].PostID, rows[j].PostID)
+	})
+	return limit(rows, arg.Limit), nil
+}
@@ -0,0 +1,338 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.userByID(arg.UserID); !ok {
+		return database.Feed{}, foreignKeyError("users", arg.UserID)
+	}
+	for _, feed := range s.feeds {
+		if feed.Url == arg.Url {
+			return database.Feed{}, uniqueError("feeds_url_key")
+		}
+	}
+	now := s.now()
+	feed := database.Feed{
+		ID: uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name: arg.Name,
+		Url: arg.Url,
+		UserID: arg.UserID,
+	}
+	s.feeds = append(s.feeds, feed)
+	return feed, nil
+}
+
+
+// DeleteFeed removes the feed with its follows and posts, like the
+// ON DELETE CASCADE clauses of the schema.
+func (s *Store) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	feeds := s.feeds[:0]
+	for _, feed := range s.feeds {
+		if feed.ID != id {
+			feeds = append(feeds, feed)
+		}
+	}
+	s.feeds = feeds
+	follows := s.follows[:0]
+	for _, follow := range s.follows {
+		if follow.FeedID != id {
+			follows = append(follows, follow)
+		}
+	}
+	s.follows = follows
+	s.deletePosts(func(post database.Post) bool {
+		return post.FeedID != id
+	})
+	return nil
+}
+
+
+func (s *Store) GetFeed(ctx context.Context, url string) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, feed := range s.feeds {
+		if feed.Url == url {
+			return feed, nil
+		}
+	}
+	return database.Feed{}, sql.ErrNoRows
+}
+
+
+func (s *Store) GetFeeds(ctx context.Context) ([]database.GetFeedsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetFeedsRow
+	for _, feed := range s.feeds {
+		user, ok := s.userByID(feed.UserID)
+		if !ok {
+			continue
+		}
+		rows = append(rows, database.GetFeedsRow{
+			Name: feed.Name,
+			Url: feed.Url,
+			UserName: user.Name,
+		})
+	}
+	return rows, nil
+}
+
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.userByID(arg.UserID)
+	if !ok {
+		return database.CreateFeedFollowRow{}, foreignKeyError("users", arg.UserID)
+	}
+	feed, ok := s.feedByID(arg.FeedID)
+	if !ok {
+		return database.CreateFeedFollowRow{}, foreignKeyError("feeds", arg.FeedID)
+	}
+	if s.isFollowing(arg.UserID, arg.FeedID) {
+		return database.CreateFeedFollowRow{}, uniqueError("feed_follows_user_id_feed_id_key")
+	}
+	now := s.now()
+	follow := database.FeedFollow{
+		ID: uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID: arg.UserID,
+		FeedID: arg.FeedID,
+	}
+	s.follows = append(s.follows, follow)
+	return database.CreateFeedFollowRow{
+		ID: follow.ID,
+		CreatedAt: follow.CreatedAt,
+		UpdatedAt: follow.UpdatedAt,
+		UserID: follow.UserID,
+		FeedID: follow.FeedID,
+		Folder: follow.Folder,
+		UserName: user.Name,
+		FeedName: feed.Name,
+	}, nil
+}
+
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetFeedFollowsForUserRow
+	for _, follow := range s.follows {
+		if follow.UserID != userID {
+			continue
+		}
+		user, _ := s.userByID(follow.UserID)
+		feed, _ := s.feedByID(follow.FeedID)
+		var unread int64
+		for _, post := range s.posts {
+			if post.FeedID == feed.ID && !s.isRead(userID, post.ID) {
+				unread++
+			}
+		}
+		rows = append(rows, database.GetFeedFollowsForUserRow{
+			UserName: user.Name,
+			FeedName: feed.Name,
+			FeedUrl: feed.Url,
+			UnreadCount: unread,
+		})
+	}
+	return rows, nil
+}
+
+
+func (s *Store) SetFeedFollowFolder(ctx context.Context, arg database.SetFeedFollowFolderParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.follows {
+		if s.follows[i].UserID == arg.UserID && s.follows[i].FeedID == arg.FeedID {
+			s.follows[i].Folder = arg.Folder
+			s.follows[i].UpdatedAt = s.now()
+		}
+	}
+	return nil
+}
+
+
+// GetFollowedFeedsForUser orders by folder, feeds outside any folder
+// first, then by name.
+func (s *Store) GetFollowedFeedsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFollowedFeedsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetFollowedFeedsForUserRow
+	for _, follow := range s.follows {
+		if follow.UserID != userID {
+			continue
+		}
+		feed, _ := s.feedByID(follow.FeedID)
+		rows = append(rows, database.GetFollowedFeedsForUserRow{
+			Name: feed.Name,
+			Url: feed.Url,
+			SiteUrl: feed.SiteUrl,
+			Folder: follow.Folder,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Folder.Valid != b.Folder.Valid {
+			return !a.Folder.Valid
+		}
+		if a.Folder.String != b.Folder.String {
+			return a.Folder.String < b.Folder.String
+		}
+		return a.Name < b.Name
+	})
+	return rows, nil
+}
+
+
+func (s *Store) UnfollowFeed(ctx context.Context, arg database.UnfollowFeedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.follows[:0]
+	for _, follow := range s.follows {
+		if follow.UserID != arg.UserID || follow.FeedID != arg.FeedID {
+			kept = append(kept, follow)
+		}
+	}
+	s.follows = kept
+	return nil
+}
+
+
+// ClaimNextFeedToFetch picks the due feed fetched longest ago, never
+// fetched feeds first, stamps it as fetched now and leases it for
+// leaseSeconds.
+func (s *Store) ClaimNextFeedToFetch(ctx context.Context, leaseSeconds int32) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	var next *database.Feed
+	for i := range s.feeds {
+		feed := &s.feeds[i]
+		if feed.NextFetchAt.Valid && feed.NextFetchAt.Time.After(now) {
+			continue
+		}
+		if next == nil || fetchedBefore(feed.LastFeteched, next.LastFeteched) {
+			next = feed
+		}
+	}
+	if next == nil {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	next.LastFeteched = sql.NullTime{Time: now, Valid: true}
+	next.NextFetchAt = sql.NullTime{Time: now.Add(time.Duration(leaseSeconds)*time.Second), Valid: true}
+	next.UpdatedAt = now
+	return *next, nil
+}
+
+
+func fetchedBefore(a, b sql.NullTime) bool {
+	if !a.Valid || !b.Valid {
+		return !a.Valid && b.Valid
+	}
+	return a.Time.Before(b.Time)
+}
+
+
+// GetFailingFeeds orders by failure count, most failures first, then by
+// name.
+func (s *Store) GetFailingFeeds(ctx context.Context) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var feeds []database.Feed
+	for _, feed := range s.feeds {
+		if feed.ConsecutiveFailures > 0 {
+			feeds = append(feeds, feed)
+		}
+	}
+	sort.SliceStable(feeds, func(i, j int) bool {
+		if feeds[i].ConsecutiveFailures != feeds[j].ConsecutiveFailures {
+			return feeds[i].ConsecutiveFailures > feeds[j].ConsecutiveFailures
+		}
+		return feeds[i].Name < feeds[j].Name
+	})
+	return feeds, nil
+}
+
+
+func (s *Store) updateFeed(id uuid.UUID, update func(*database.Feed)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if feed, ok := s.feedByID(id); ok {
+		update(feed)
+		feed.UpdatedAt = s.now()
+	}
+	return nil
+}
+
+
+func (s *Store) UpdateFeedCache(ctx context.Context, arg database.UpdateFeedCacheParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.Etag = arg.Etag
+		feed.LastModified = arg.LastModified
+	})
+}
+
+
+func (s *Store) RecordFeedSuccess(ctx context.Context, arg database.RecordFeedSuccessParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.LastError = sql.NullString{}
+		feed.ConsecutiveFailures = 0
+		feed.LastSuccessAt = sql.NullTime{Time: s.now(), Valid: true}
+		feed.NextFetchAt = arg.NextFetchAt
+	})
+}
+
+
+func (s *Store) RecordFeedFailure(ctx context.Context, arg database.RecordFeedFailureParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.LastError = arg.LastError
+		feed.ConsecutiveFailures++
+		feed.NextFetchAt = arg.NextFetchAt
+	})
+}
+
+
+func (s *Store) UpdateFeedSchedule(ctx context.Context, arg database.UpdateFeedScheduleParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.HintedIntervalSeconds = arg.HintedIntervalSeconds
+		feed.SkipHours = arg.SkipHours
+		feed.SkipDays = arg.SkipDays
+	})
+}
+
+
+func (s *Store) SetFeedIntervalOverride(ctx context.Context, arg database.SetFeedIntervalOverrideParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.IntervalOverrideSeconds = arg.IntervalOverrideSeconds
+	})
+}
+
+
+func (s *Store) SetFeedAdaptiveInterval(ctx context.Context, arg database.SetFeedAdaptiveIntervalParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.AdaptiveIntervalSeconds = arg.AdaptiveIntervalSeconds
+	})
+}
+
+
+func (s *Store) SetFeedSiteURL(ctx context.Context, arg database.SetFeedSiteURLParams) error {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) {
+		feed.SiteUrl = arg.SiteUrl
+	})
+}
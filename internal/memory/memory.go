@@ -0,0 +1,169 @@
+// Package memory is an in-memory database.Querier. It keeps the
+// constraints, cascades and orderings of the PostgreSQL schema so handlers
+// behave the same against it, and is meant for tests and trying gator out
+// without a database.
+package memory
+
+import (
+	"fmt"
+	"gator/internal/database"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type stateKey struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+// Store holds every table as a slice in insertion order, which stands in
+// for PostgreSQL's unspecified row order.
+type Store struct {
+	mu sync.Mutex
+	now func() time.Time
+
+	users []database.User
+	feeds []database.Feed
+	follows []database.FeedFollow
+	posts []database.Post
+	states map[stateKey]database.PostState
+	stars map[stateKey]database.PostStar
+	enclosures []database.Enclosure
+}
+
+var _ database.Querier = (*Store)(nil)
+
+func New() *Store {
+	return &Store{
+		now: time.Now,
+		states: make(map[stateKey]database.PostState),
+		stars: make(map[stateKey]database.PostStar),
+	}
+}
+
+// SetClock replaces the clock used for now(), so time-dependent queries
+// can be driven deterministically.
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+
+func (s *Store) userByID(id uuid.UUID) (*database.User, bool) {
+	for i := range s.users {
+		if s.users[i].ID == id {
+			return &s.users[i], true
+		}
+	}
+	return nil, false
+}
+
+
+func (s *Store) feedByID(id uuid.UUID) (*database.Feed, bool) {
+	for i := range s.feeds {
+		if s.feeds[i].ID == id {
+			return &s.feeds[i], true
+		}
+	}
+	return nil, false
+}
+
+
+func (s *Store) postByID(id uuid.UUID) (*database.Post, bool) {
+	for i := range s.posts {
+		if s.posts[i].ID == id {
+			return &s.posts[i], true
+		}
+	}
+	return nil, false
+}
+
+
+func (s *Store) isFollowing(userID, feedID uuid.UUID) bool {
+	for _, follow := range s.follows {
+		if follow.UserID == userID && follow.FeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+
+func (s *Store) isRead(userID, postID uuid.UUID) bool {
+	return s.states[stateKey{userID, postID}].Read
+}
+
+
+func foreignKeyError(table string, id uuid.UUID) error {
+	return fmt.Errorf("insert violates foreign key constraint: no %s with id %s", table, id)
+}
+
+
+func uniqueError(constraint string) error {
+	return fmt.Errorf("%w %q", database.ErrUniqueViolation, constraint)
+}
+
+
+// deletePosts removes the posts keep rejects, with their read states,
+// stars and enclosures.
+func (s *Store) deletePosts(keep func(database.Post) bool) int64 {
+	var count int64
+	removed := make(map[uuid.UUID]bool)
+	kept := s.posts[:0]
+	for _, post := range s.posts {
+		if keep(post) {
+			kept = append(kept, post)
+			continue
+		}
+		count++
+		removed[post.ID] = true
+		for key := range s.states {
+			if key.PostID == post.ID {
+				delete(s.states, key)
+			}
+		}
+		for key := range s.stars {
+			if key.PostID == post.ID {
+				delete(s.stars, key)
+			}
+		}
+	}
+	s.posts = kept
+
+	enclosures := s.enclosures[:0]
+	for _, enclosure := range s.enclosures {
+		if !removed[enclosure.PostID] {
+			enclosures = append(enclosures, enclosure)
+		}
+	}
+	s.enclosures = enclosures
+	return count
+}
+
+
+// deleteFeeds removes the feeds keep rejects, with their follows and
+// posts.
+func (s *Store) deleteFeeds(keep func(database.Feed) bool) {
+	removed := make(map[uuid.UUID]bool)
+	kept := s.feeds[:0]
+	for _, feed := range s.feeds {
+		if keep(feed) {
+			kept = append(kept, feed)
+		} else {
+			removed[feed.ID] = true
+		}
+	}
+	s.feeds = kept
+
+	follows := s.follows[:0]
+	for _, follow := range s.follows {
+		if !removed[follow.FeedID] {
+			follows = append(follows, follow)
+		}
+	}
+	s.follows = follows
+	s.deletePosts(func(post database.Post) bool { return !removed[post.FeedID] })
+}
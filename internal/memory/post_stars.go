@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"gator/internal/database"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// StarPost replaces the note of a post that is already starred.
+func (s *Store) StarPost(ctx context.Context, arg database.StarPostParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.userByID(arg.UserID); !ok {
+		return foreignKeyError("users", arg.UserID)
+	}
+	if _, ok := s.postByID(arg.PostID); !ok {
+		return foreignKeyError("posts", arg.PostID)
+	}
+	now := s.now()
+	key := stateKey{arg.UserID, arg.PostID}
+	star, ok := s.stars[key]
+	if !ok {
+		star = database.PostStar{
+			UserID: arg.UserID,
+			PostID: arg.PostID,
+			CreatedAt: now,
+		}
+	}
+	star.UpdatedAt = now
+	star.Note = arg.Note
+	s.stars[key] = star
+	return nil
+}
+
+
+func (s *Store) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := stateKey{arg.UserID, arg.PostID}
+	if _, ok := s.stars[key]; !ok {
+		return 0, nil
+	}
+	delete(s.stars, key)
+	return 1, nil
+}
+
+
+// GetStarredPostsForUser orders the most recently starred first.
+func (s *Store) GetStarredPostsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetStarredPostsForUserRow
+	for _, post := range s.posts {
+		star, ok := s.stars[stateKey{userID, post.ID}]
+		if !ok {
+			continue
+		}
+		feed, _ := s.feedByID(post.FeedID)
+		rows = append(rows, database.GetStarredPostsForUserRow{
+			ID: post.ID,
+			CreatedAt: post.CreatedAt,
+			UpdatedAt: post.UpdatedAt,
+			Title: post.Title,
+			Url: post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID: post.FeedID,
+			Guid: post.Guid,
+			Content: post.Content,
+			FeedName: feed.Name,
+			Note: star.Note,
+			StarredAt: star.CreatedAt,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].StarredAt.After(rows[j].StarredAt)
+	})
+	return rows, nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// setRead upserts the read state of a post for a user.
+func (s *Store) setRead(userID, postID uuid.UUID, read bool) error {
+	if _, ok := s.userByID(userID); !ok {
+		return foreignKeyError("users", userID)
+	}
+	if _, ok := s.postByID(postID); !ok {
+		return foreignKeyError("posts", postID)
+	}
+	now := s.now()
+	key := stateKey{userID, postID}
+	state, ok := s.states[key]
+	if !ok {
+		state = database.PostState{
+			UserID: userID,
+			PostID: postID,
+			CreatedAt: now,
+		}
+	}
+	state.UpdatedAt = now
+	state.Read = read
+	state.ReadAt = sql.NullTime{}
+	if read {
+		state.ReadAt = sql.NullTime{Time: now, Valid: true}
+	}
+	s.states[key] = state
+	return nil
+}
+
+
+func (s *Store) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.setRead(arg.UserID, arg.PostID, true)
+}
+
+
+func (s *Store) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.setRead(arg.UserID, arg.PostID, false)
+}
+
+
+// MarkFeedRead counts only the posts that were unread, like the
+// conditional ON CONFLICT clause of the SQL query, and skips feeds the
+// user doesn't follow.
+func (s *Store) MarkFeedRead(ctx context.Context, arg database.MarkFeedReadParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	if !s.isFollowing(arg.UserID, arg.FeedID) {
+		return count, nil
+	}
+	for _, post := range s.posts {
+		if post.FeedID != arg.FeedID || s.isRead(arg.UserID, post.ID) {
+			continue
+		}
+		err := s.setRead(arg.UserID, post.ID, true)
+		if err != nil { return count, err }
+		count++
+	}
+	return count, nil
+}
+
+
+func (s *Store) MarkPostsReadBefore(ctx context.Context, arg database.MarkPostsReadBeforeParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	for _, post := range s.posts {
+		if !s.isFollowing(arg.UserID, post.FeedID) || !postDate(post).Before(arg.Before) || s.isRead(arg.UserID, post.ID) {
+			continue
+		}
+		err := s.setRead(arg.UserID, post.ID, true)
+		if err != nil { return count, err }
+		count++
+	}
+	return count, nil
+}
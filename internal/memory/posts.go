@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeletePostsOlderThan keeps starred posts, like the SQL query.
+func (s *Store) DeletePostsOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	starred := make(map[uuid.UUID]bool)
+	for key := range s.stars {
+		starred[key.PostID] = true
+	}
+	return s.deletePosts(func(post database.Post) bool {
+		return starred[post.ID] || !postDate(post).Before(before)
+	}), nil
+}
+
+
+func (s *Store) GetFeedPublishDates(ctx context.Context, arg database.GetFeedPublishDatesParams) ([]sql.NullTime, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var dates []sql.NullTime
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID && post.PublishedAt.Valid {
+			dates = append(dates, post.PublishedAt)
+		}
+	}
+	sort.SliceStable(dates, func(i, j int) bool {
+		return dates[i].Time.After(dates[j].Time)
+	})
+	return limit(dates, arg.Limit), nil
+}
+
+
+// GetPostsForUser orders newest first, dating undated posts by when they
+// were stored.
+func (s *Store) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetPostsForUserRow
+	for _, post := range s.posts {
+		if !s.isFollowing(arg.UserID, post.FeedID) {
+			continue
+		}
+		read := s.isRead(arg.UserID, post.ID)
+		if arg.UnreadOnly && read {
+			continue
+		}
+		feed, _ := s.feedByID(post.FeedID)
+		rows = append(rows, database.GetPostsForUserRow{
+			ID: post.ID,
+			CreatedAt: post.CreatedAt,
+			UpdatedAt: post.UpdatedAt,
+			Title: post.Title,
+			Url: post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID: post.FeedID,
+			Guid: post.Guid,
+			Content: post.Content,
+			FeedName: feed.Name,
+			FeedUrl: feed.Url,
+			Read: read,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return s.newerFirst(rows[i].ID, rows[j].ID)
+	})
+	return limit(rows, arg.Limit), nil
+}
+
+
+// newerFirst orders posts by COALESCE(published_at, created_at) DESC.
+func (s *Store) newerFirst(a, b uuid.UUID) bool {
+	postA, _ := s.postByID(a)
+	postB, _ := s.postByID(b)
+	return postDate(*postA).After(postDate(*postB))
+}
+
+
+// postDate is COALESCE(published_at, created_at).
+func postDate(post database.Post) time.Time {
+	if post.PublishedAt.Valid {
+		return post.PublishedAt.Time
+	}
+	return post.CreatedAt
+}
+
+
+func limit[T any](rows []T, n int32) []T {
+	if n >= 0 && int(n) < len(rows) {
+		return rows[:n]
+	}
+	return rows
+}
+
+
+func (s *Store) AdoptLegacyPost(ctx context.Context, arg database.AdoptLegacyPostParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	legacy := -1
+	for i, post := range s.posts {
+		if post.FeedID != arg.FeedID {
+			continue
+		}
+		if post.Guid == arg.Guid {
+			return nil
+		}
+		if post.Url == arg.Url && post.Guid == post.Url {
+			legacy = i
+		}
+	}
+	if legacy >= 0 {
+		s.posts[legacy].Guid = arg.Guid
+	}
+	return nil
+}
+
+
+// UpsertPost matches posts on (feed_id, guid) and, like the SQL query,
+// returns sql.ErrNoRows when nothing about the post changed.
+func (s *Store) UpsertPost(ctx context.Context, arg database.UpsertPostParams) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.feedByID(arg.FeedID); !ok {
+		return database.Post{}, foreignKeyError("feeds", arg.FeedID)
+	}
+	now := s.now()
+	for i, post := range s.posts {
+		if post.FeedID != arg.FeedID || post.Guid != arg.Guid {
+			continue
+		}
+		if post.Title == arg.Title && post.Url == arg.Url && post.Description == arg.Description && post.Content == arg.Content {
+			return database.Post{}, sql.ErrNoRows
+		}
+		post.Title = arg.Title
+		post.Url = arg.Url
+		post.Description = arg.Description
+		post.Content = arg.Content
+		if arg.PublishedAt.Valid {
+			post.PublishedAt = arg.PublishedAt
+		}
+		post.UpdatedAt = now
+		s.posts[i] = post
+		return post, nil
+	}
+	post := database.Post{
+		ID: uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Title: arg.Title,
+		Url: arg.Url,
+		Description: arg.Description,
+		PublishedAt: arg.PublishedAt,
+		FeedID: arg.FeedID,
+		Guid: arg.Guid,
+		Content: arg.Content,
+	}
+	s.posts = append(s.posts, post)
+	return post, nil
+}
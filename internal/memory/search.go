@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"gator/internal/database"
+	"gator/internal/websearch"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopWords are dropped from queries and documents, as the english text
+// search configuration does.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "with": true,
+}
+
+const (
+	titleWeight = 1.0
+	descriptionWeight = 0.4
+	snippetWords = 35
+)
+
+// term is one search term: a word, or a quoted phrase of several.
+type term struct {
+	stems []string
+	negated bool
+}
+
+// SearchPosts approximates PostgreSQL full-text search: queries use the
+// same web search syntax, words are matched after light stemming, title
+// matches rank above description matches and the snippet shows up to 35
+// words around the first match with **markers**.
+func (s *Store) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := parseQuery(arg.Query)
+	var rows []database.SearchPostsRow
+	for _, post := range s.posts {
+		if !arg.AllFeeds && !s.isFollowing(arg.UserID, post.FeedID) {
+			continue
+		}
+		title := stems(post.Title)
+		description := stems(post.Description.String)
+		if !query.matches(title, description) {
+			continue
+		}
+		feed, _ := s.feedByID(post.FeedID)
+		rows = append(rows, database.SearchPostsRow{
+			ID: post.ID,
+			Title: post.Title,
+			Url: post.Url,
+			PublishedAt: post.PublishedAt,
+			FeedName: feed.Name,
+			Rank: query.rank(title, description),
+			Snippet: query.snippet(post.Title + " " + post.Description.String),
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Rank != rows[j].Rank {
+			return rows[i].Rank > rows[j].Rank
+		}
+		return s.newerFirst(rows[i].ID, rows[j].ID)
+	})
+	return limit(rows, arg.Limit), nil
+}
+
+
+// searchQuery is a disjunction of groups that each need all their terms.
+type searchQuery [][]term
+
+func parseQuery(text string) searchQuery {
+	var query searchQuery
+	for _, terms := range websearch.Parse(text) {
+		var group []term
+		for _, t := range terms {
+			words := stems(t.Text)
+			if len(words) > 0 {
+				group = append(group, term{stems: words, negated: t.Negated})
+			}
+		}
+		if len(group) > 0 {
+			query = append(query, group)
+		}
+	}
+	return query
+}
+
+
+func (q searchQuery) matches(title, description []string) bool {
+	for _, group := range q {
+		matched := true
+		positive := false
+		for _, t := range group {
+			found := count(t, title) + count(t, description) > 0
+			if found == t.negated {
+				matched = false
+				break
+			}
+			positive = positive || !t.negated
+		}
+		if matched && positive {
+			return true
+		}
+	}
+	return false
+}
+
+
+func (q searchQuery) rank(title, description []string) float32 {
+	var score float64
+	for _, group := range q {
+		for _, t := range group {
+			if t.negated {
+				continue
+			}
+			score += titleWeight*float64(count(t, title)) + descriptionWeight*float64(count(t, description))
+		}
+	}
+	return float32(score / (score + 10))
+}
+
+
+// snippet marks every word of text matching a positive term and keeps a
+// window of words starting a little before the first match.
+func (q searchQuery) snippet(text string) string {
+	words := strings.Fields(text)
+	marked := make([]bool, len(words))
+	first := -1
+	for i := range words {
+		for _, group := range q {
+			for _, t := range group {
+				if t.negated {
+					continue
+				}
+				for _, stem := range t.stems {
+					if word := stems(words[i]); len(word) == 1 && word[0] == stem {
+						marked[i] = true
+					}
+				}
+			}
+		}
+		if marked[i] && first < 0 {
+			first = i
+		}
+	}
+
+	start := max(first-5, 0)
+	end := min(start+snippetWords, len(words))
+	var out []string
+	for i := start; i < end; i++ {
+		if marked[i] {
+			out = append(out, "**"+words[i]+"**")
+		} else {
+			out = append(out, words[i])
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+
+// count returns how often the stems of t occur, in order, in words.
+func count(t term, words []string) int {
+	n := 0
+	for i := 0; i+len(t.stems) <= len(words); i++ {
+		match := true
+		for j, stem := range t.stems {
+			if words[i+j] != stem {
+				match = false
+				break
+			}
+		}
+		if match {
+			n++
+		}
+	}
+	return n
+}
+
+
+// stems splits text into lower-case words, drops stop words and strips
+// common English suffixes.
+func stems(text string) []string {
+	var out []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		for _, suffix := range []string{"ing", "ed", "es", "s"} {
+			if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+				word = strings.TrimSuffix(word, suffix)
+				break
+			}
+		}
+		out = append(out, word)
+	}
+	return out
+}
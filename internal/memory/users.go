@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateUser(ctx context.Context, name string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Name == name {
+			return database.User{}, uniqueError("users_name_key")
+		}
+	}
+	now := s.now()
+	user := database.User{
+		ID: uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name: name,
+	}
+	s.users = append(s.users, user)
+	return user, nil
+}
+
+
+func (s *Store) GetUser(ctx context.Context, name string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+
+func (s *Store) GetUsers(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for _, user := range s.users {
+		names = append(names, user.Name)
+	}
+	return names, nil
+}
+
+
+// TruncateUsers deletes every user and, through the cascades, everything
+// else.
+func (s *Store) TruncateUsers(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = nil
+	s.deleteFeeds(func(database.Feed) bool { return false })
+	clear(s.states)
+	clear(s.stars)
+	return nil
+}
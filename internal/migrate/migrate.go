@@ -0,0 +1,67 @@
+// Package migrate applies the schema migrations embedded in the binary,
+// recording them in the same goose_db_version table as the goose CLI.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+
+	postgres "gator/sql/schema"
+	sqlite "gator/sql/sqlite/schema"
+
+	"github.com/pressly/goose/v3"
+)
+
+type Dialect = goose.Dialect
+
+const (
+	Postgres = goose.DialectPostgres
+	SQLite = goose.DialectSQLite3
+)
+
+// Migrator runs the migrations for one database.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func New(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	var migrations fs.FS = postgres.FS
+	if dialect == SQLite {
+		migrations = sqlite.FS
+	}
+	provider, err := goose.NewProvider(dialect, db, migrations)
+	if err != nil { return nil, fmt.Errorf("error loading migrations: %v", err) }
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the most recent migration.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Check returns an error unless the database schema is exactly the one
+// this binary was built against.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil { return fmt.Errorf("error reading schema version: %v", err) }
+	if current > target {
+		return fmt.Errorf("database schema is at version %d, newer than the %d this gator supports; upgrade gator", current, target)
+	}
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil { return fmt.Errorf("error reading schema version: %v", err) }
+	if pending {
+		return fmt.Errorf("database schema is at version %d but gator needs version %d; run 'gator migrate up'", current, target)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+// Package output renders the listings of gator commands as an aligned
+// table for people, or as JSON, JSON Lines or CSV for scripts.
+package output
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	Table Format = "table"
+	JSON Format = "json"
+	JSONLines Format = "jsonl"
+	CSV Format = "csv"
+)
+
+// Formats lists the accepted values of --output.
+var Formats = []Format{Table, JSON, JSONLines, CSV}
+
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q, expected table, json, jsonl or csv", name)
+}
+
+// Column is one field of a listing. Name is the JSON key and CSV header;
+// the table header is Name in upper case. Value returns a string, number,
+// bool, time.Time, Marked, fmt.Stringer or nil for a missing value.
+type Column[T any] struct {
+	Name string
+	Value func(T) any
+}
+
+// Marked is text with **match** markers, such as a search snippet. Tables
+// show the matches in bold, the other formats drop the markers.
+type Marked string
+
+// Write renders rows in format. JSON is an array of objects with the keys
+// in column order, JSON Lines one object per line.
+func Write[T any](w io.Writer, format Format, columns []Column[T], rows []T) error {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	records := make([][]any, len(rows))
+	for i, row := range rows {
+		records[i] = make([]any, len(columns))
+		for j, column := range columns {
+			records[i][j] = column.Value(row)
+		}
+	}
+
+	switch format {
+	case JSON:
+		return writeJSON(w, names, records)
+	case JSONLines:
+		return writeJSONLines(w, names, records)
+	case CSV:
+		return writeCSV(w, names, records)
+	default:
+		return writeTable(w, names, records)
+	}
+}
+
+
+func writeTable(w io.Writer, names []string, records [][]any) error {
+	cells := make([][]string, 0, len(records)+1)
+	header := make([]string, len(names))
+	for i, name := range names {
+		header[i] = strings.ToUpper(strings.ReplaceAll(name, "_", " "))
+	}
+	cells = append(cells, header)
+	for _, record := range records {
+		row := make([]string, len(record))
+		for i, value := range record {
+			row[i] = tableCell(value)
+		}
+		cells = append(cells, row)
+	}
+
+	widths := make([]int, len(names))
+	for _, row := range cells {
+		for i, cell := range row {
+			widths[i] = max(widths[i], visibleWidth(cell))
+		}
+	}
+	var b strings.Builder
+	for _, row := range cells {
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(cell)+2))
+			}
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+
+func writeJSON(w io.Writer, names []string, records [][]any) error {
+	objects := make([]object, len(records))
+	for i, record := range records {
+		objects[i] = object{names: names, values: record}
+	}
+	data, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil { return fmt.Errorf("error encoding JSON: %v", err) }
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+
+func writeJSONLines(w io.Writer, names []string, records [][]any) error {
+	for _, record := range records {
+		data, err := json.Marshal(object{names: names, values: record})
+		if err != nil { return fmt.Errorf("error encoding JSON: %v", err) }
+		_, err = w.Write(append(data, '\n'))
+		if err != nil { return err }
+	}
+	return nil
+}
+
+
+func writeCSV(w io.Writer, names []string, records [][]any) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(names)
+	if err != nil { return err }
+	for _, record := range records {
+		row := make([]string, len(record))
+		for i, value := range record {
+			row[i] = plainCell(value)
+		}
+		err = writer.Write(row)
+		if err != nil { return err }
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+
+// object marshals as a JSON object with its keys in column order.
+type object struct {
+	names []string
+	values []any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, name := range o.names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil { return nil, err }
+		value := o.values[i]
+		if marked, ok := value.(Marked); ok {
+			value = unmark(marked)
+		}
+		data, err := json.Marshal(value)
+		if err != nil { return nil, err }
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(data)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+
+func tableCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case time.Time:
+		return v.Local().Format("2006-01-02 15:04")
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', 3, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	case Marked:
+		return bold(v)
+	}
+	return oneLine(plainCell(value))
+}
+
+
+func plainCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case Marked:
+		return unmark(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+
+// bold turns **match** markers into bold text.
+func bold(text Marked) string {
+	parts := strings.Split(oneLine(string(text)), "**")
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString("\033[1m" + part + "\033[0m")
+		} else {
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+
+func unmark(text Marked) string {
+	return strings.ReplaceAll(string(text), "**", "")
+}
+
+
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+
+// visibleWidth counts the runes of text that are not part of an ANSI
+// escape sequence.
+func visibleWidth(text string) int {
+	width := 0
+	escape := false
+	for _, r := range text {
+		switch {
+		case escape:
+			escape = r < '@' || r > '~' || r == '['
+		case r == '\033':
+			escape = true
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+
+// NullString returns the string, or nil when it is not set.
+func NullString(s sql.NullString) any {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
+
+// NullTime returns the time, or nil when it is not set.
+func NullTime(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
@@ -0,0 +1,51 @@
+// Package publish turns a user's timeline into a feed of its own.
+package publish
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"gator/rss"
+	"time"
+)
+
+// UserFeed collects the newest posts from the feeds the user follows into
+// a feed. Item ids are derived from post ids so they stay stable across
+// renders.
+func UserFeed(ctx context.Context, db database.Querier, user *database.User, limit int32, link string) (*rss.Output, error) {
+	posts, err := db.GetPostsForUser(ctx, database.GetPostsForUserParams{
+		UserID: user.ID,
+		Limit:  limit,
+	})
+	if err != nil { return nil, fmt.Errorf("error listing posts: %v", err) }
+
+	out := &rss.Output{
+		ID:          "urn:uuid:" + user.ID.String(),
+		Title:       fmt.Sprintf("%s's gator timeline", user.Name),
+		Description: fmt.Sprintf("Posts from the feeds %s follows on gator", user.Name),
+		Author:      user.Name,
+		Link:        link,
+	}
+	for _, post := range posts {
+		published := post.CreatedAt
+		if post.PublishedAt.Valid {
+			published = post.PublishedAt.Time
+		}
+		if published.After(out.Updated) {
+			out.Updated = published
+		}
+		out.Items = append(out.Items, rss.OutputItem{
+			ID:          "urn:uuid:" + post.ID.String(),
+			Title:       post.Title,
+			Link:        post.Url,
+			Description: post.Description.String,
+			Published:   published,
+			SourceTitle: post.FeedName,
+			SourceURL:   post.FeedUrl,
+		})
+	}
+	if out.Updated.IsZero() {
+		out.Updated = time.Now()
+	}
+	return out, nil
+}
@@ -0,0 +1,35 @@
+package reader
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// openURL hands link to the system browser without waiting for it to exit.
+// Links come from feeds, so only http and https are opened: the system
+// opener would also launch file: or custom-handler URIs.
+func openURL(link string) error {
+	u, err := url.Parse(link)
+	if err != nil { return fmt.Errorf("error opening browser: %v", err) }
+	scheme := strings.ToLower(u.Scheme)
+	if (scheme != "http" && scheme != "https") || u.Host == "" {
+		return fmt.Errorf("not opening %q: only http and https links are opened", link)
+	}
+
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", u.String())
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", u.String())
+	default:
+		command = exec.Command("xdg-open", u.String())
+	}
+	err = command.Start()
+	if err != nil { return fmt.Errorf("error opening browser: %v", err) }
+	go command.Wait()
+	return nil
+}
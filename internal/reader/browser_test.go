@@ -0,0 +1,23 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenURLRejectsOtherSchemes(t *testing.T) {
+	for _, link := range []string{
+		"file:///etc/passwd",
+		"javascript:alert(1)",
+		"ms-settings:",
+		"vscode://file/tmp/x",
+		"//example.com/post",
+		"https:///no-host",
+		"/relative/post",
+	} {
+		err := openURL(link)
+		if err == nil || !strings.Contains(err.Error(), "only http and https") {
+			t.Errorf("openURL(%q) = %v, want it refused", link, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package reader
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// blockElements start on a line of their own when rendered as text.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true,
+	"blockquote": true, "pre": true, "ul": true, "ol": true,
+	"table": true, "tr": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "hr": true, "figure": true,
+}
+
+// HTMLToText renders an HTML fragment, such as a post description, as
+// plain paragraphs. Links keep their target after the link text.
+func HTMLToText(fragment string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	var out strings.Builder
+	var href string
+	skip := 0
+	pre := 0
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return tidy(out.String())
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := string(tokenizer.Text())
+			if pre == 0 {
+				text = strings.Join(strings.Fields(text), " ")
+				if text == "" {
+					continue
+				}
+				if hasSpaceBefore(tokenizer.Raw()) {
+					text = " " + text
+				}
+				if hasSpaceAfter(tokenizer.Raw()) {
+					text += " "
+				}
+			}
+			out.WriteString(text)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := string(name)
+			switch {
+			case tag == "script" || tag == "style":
+				skip++
+			case tag == "pre":
+				pre++
+				out.WriteString("\n\n")
+			case tag == "br":
+				out.WriteString("\n")
+			case tag == "li":
+				out.WriteString("\n• ")
+			case tag == "img":
+				alt := attr(tokenizer, hasAttr, "alt")
+				if alt != "" {
+					out.WriteString("[image: " + alt + "]")
+				}
+			case tag == "a":
+				href = attr(tokenizer, hasAttr, "href")
+			case blockElements[tag]:
+				out.WriteString("\n\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			switch {
+			case tag == "script" || tag == "style":
+				if skip > 0 {
+					skip--
+				}
+			case tag == "pre":
+				if pre > 0 {
+					pre--
+				}
+				out.WriteString("\n\n")
+			case tag == "a":
+				if href != "" && !strings.HasPrefix(href, "#") {
+					out.WriteString(" <" + href + ">")
+				}
+				href = ""
+			case blockElements[tag]:
+				out.WriteString("\n\n")
+			}
+		}
+	}
+}
+
+
+func attr(tokenizer *html.Tokenizer, more bool, key string) string {
+	for more {
+		var name, value []byte
+		name, value, more = tokenizer.TagAttr()
+		if string(name) == key {
+			return string(value)
+		}
+	}
+	return ""
+}
+
+
+func hasSpaceBefore(raw []byte) bool {
+	r, _ := utf8.DecodeRune(raw)
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
+
+
+func hasSpaceAfter(raw []byte) bool {
+	r, _ := utf8.DecodeLastRune(raw)
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
+
+
+// tidy trims each line and collapses runs of blank lines into one.
+func tidy(text string) string {
+	var lines []string
+	blank := true
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, strings.TrimLeft(line, " "))
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+
+// wrap breaks text into lines no wider than width, keeping explicit line
+// breaks.
+func wrap(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
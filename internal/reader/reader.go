@@ -0,0 +1,418 @@
+// Package reader is a full-screen terminal UI for reading posts.
+package reader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gator/internal/database"
+	"os"
+
+	"github.com/google/uuid"
+	"golang.org/x/term"
+)
+
+// postLimit caps how many posts are loaded when the reader opens.
+const postLimit = 500
+
+type pane int
+
+const (
+	feedsPane pane = iota
+	postsPane
+	detailPane
+)
+
+type feedEntry struct {
+	Name string
+	URL string
+	Unread int64
+}
+
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyTab
+	keyBackTab
+	keyEnter
+	keyEscape
+	keyRune
+)
+
+// Reader holds the state of one reading session.
+type Reader struct {
+	ctx context.Context
+	db database.Querier
+	user *database.User
+
+	feeds []feedEntry
+	posts []database.GetPostsForUserRow
+	starred map[uuid.UUID]bool
+
+	focus pane
+	feedIndex int
+	postIndex int
+	postTop int
+	detailTop int
+	open *database.GetPostsForUserRow
+	status string
+}
+
+// Run opens the reader on the terminal attached to stdin and blocks until
+// the user quits.
+func Run(ctx context.Context, db database.Querier, user *database.User) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("read needs an interactive terminal")
+	}
+
+	r := &Reader{ctx: ctx, db: db, user: user}
+	err := r.load()
+	if err != nil { return err }
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil { return fmt.Errorf("error entering raw mode: %v", err) }
+	defer term.Restore(fd, oldState)
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	buf := make([]byte, 32)
+	for {
+		r.draw()
+		n, err := os.Stdin.Read(buf)
+		if err != nil { return fmt.Errorf("error reading input: %v", err) }
+		k, ch := decodeKey(buf[:n])
+		if r.handle(k, ch) {
+			return nil
+		}
+	}
+}
+
+
+// load fetches the user's follows, posts and stars.
+func (r *Reader) load() error {
+	follows, err := r.db.GetFeedFollowsForUser(r.ctx, r.user.ID)
+	if err != nil { return fmt.Errorf("error listing follows: %v", err) }
+	posts, err := r.db.GetPostsForUser(r.ctx, database.GetPostsForUserParams{
+		UserID: r.user.ID,
+		Limit: postLimit,
+	})
+	if err != nil { return fmt.Errorf("error listing posts: %v", err) }
+	stars, err := r.db.GetStarredPostsForUser(r.ctx, r.user.ID)
+	if err != nil { return fmt.Errorf("error listing starred posts: %v", err) }
+
+	var total int64
+	r.feeds = []feedEntry{{Name: "All feeds"}}
+	for _, follow := range follows {
+		r.feeds = append(r.feeds, feedEntry{
+			Name: follow.FeedName,
+			URL: follow.FeedUrl,
+			Unread: follow.UnreadCount,
+		})
+		total += follow.UnreadCount
+	}
+	r.feeds[0].Unread = total
+	r.posts = posts
+	r.starred = make(map[uuid.UUID]bool, len(stars))
+	for _, star := range stars {
+		r.starred[star.ID] = true
+	}
+
+	r.feedIndex = min(r.feedIndex, len(r.feeds)-1)
+	r.postIndex = 0
+	r.postTop = 0
+	r.open = nil
+	return nil
+}
+
+
+// visiblePosts returns the posts of the selected feed.
+func (r *Reader) visiblePosts() []*database.GetPostsForUserRow {
+	url := r.feeds[r.feedIndex].URL
+	var visible []*database.GetPostsForUserRow
+	for i := range r.posts {
+		if url == "" || r.posts[i].FeedUrl == url {
+			visible = append(visible, &r.posts[i])
+		}
+	}
+	return visible
+}
+
+
+func (r *Reader) selectedPost() *database.GetPostsForUserRow {
+	visible := r.visiblePosts()
+	if r.postIndex < 0 || r.postIndex >= len(visible) {
+		return nil
+	}
+	return visible[r.postIndex]
+}
+
+
+// handle applies a key press and reports whether the reader should quit.
+func (r *Reader) handle(k key, ch rune) bool {
+	r.status = ""
+	if k == keyRune {
+		switch ch {
+		case 'q', 3:
+			return true
+		case 'j':
+			k = keyDown
+		case 'k':
+			k = keyUp
+		case 'h':
+			k = keyLeft
+		case 'l':
+			k = keyRight
+		case ' ':
+			k = keyPageDown
+		case 'g':
+			k = keyHome
+		case 'G':
+			k = keyEnd
+		case 'r':
+			r.toggleRead()
+			return false
+		case 's':
+			r.toggleStar()
+			return false
+		case 'o':
+			r.openLink()
+			return false
+		case 'R':
+			err := r.load()
+			if err != nil {
+				r.status = err.Error()
+			} else {
+				r.status = "Reloaded"
+			}
+			return false
+		}
+	}
+
+	switch k {
+	case keyTab:
+		r.focus = (r.focus + 1) % 3
+	case keyBackTab:
+		r.focus = (r.focus + 2) % 3
+	case keyLeft, keyEscape:
+		if r.focus > feedsPane {
+			r.focus--
+		}
+	case keyRight:
+		if r.focus == feedsPane {
+			r.focus = postsPane
+		} else if r.focus == postsPane {
+			r.openPost()
+		}
+	case keyEnter:
+		if r.focus == feedsPane {
+			r.focus = postsPane
+		} else {
+			r.openPost()
+		}
+	case keyUp, keyDown, keyPageUp, keyPageDown, keyHome, keyEnd:
+		r.move(k)
+	}
+	return false
+}
+
+
+func (r *Reader) move(k key) {
+	_, height := r.size()
+	step := map[key]int{
+		keyUp: -1,
+		keyDown: 1,
+		keyPageUp: -height / 2,
+		keyPageDown: height / 2,
+		keyHome: -1 << 30,
+		keyEnd: 1 << 30,
+	}[k]
+
+	switch r.focus {
+	case feedsPane:
+		index := clamp(r.feedIndex+step, 0, len(r.feeds)-1)
+		if index != r.feedIndex {
+			r.feedIndex = index
+			r.postIndex = 0
+			r.postTop = 0
+		}
+	case postsPane:
+		r.postIndex = clamp(r.postIndex+step, 0, len(r.visiblePosts())-1)
+	case detailPane:
+		r.detailTop = max(r.detailTop+step, 0)
+	}
+}
+
+
+// openPost shows the selected post in the detail pane and marks it read.
+func (r *Reader) openPost() {
+	post := r.selectedPost()
+	if post == nil {
+		return
+	}
+	r.open = post
+	r.detailTop = 0
+	r.focus = detailPane
+	if !post.Read {
+		r.setRead(post, true)
+	}
+}
+
+
+func (r *Reader) toggleRead() {
+	post := r.current()
+	if post == nil {
+		return
+	}
+	r.setRead(post, !post.Read)
+}
+
+
+func (r *Reader) setRead(post *database.GetPostsForUserRow, read bool) {
+	var err error
+	if read {
+		err = r.db.MarkPostRead(r.ctx, database.MarkPostReadParams{
+			UserID: r.user.ID,
+			PostID: post.ID,
+		})
+	} else {
+		err = r.db.MarkPostUnread(r.ctx, database.MarkPostUnreadParams{
+			UserID: r.user.ID,
+			PostID: post.ID,
+		})
+	}
+	if err != nil {
+		r.status = fmt.Sprintf("error marking post: %v", err)
+		return
+	}
+	if post.Read == read {
+		return
+	}
+	post.Read = read
+	delta := int64(1)
+	if read {
+		delta = -1
+	}
+	for i := range r.feeds {
+		if r.feeds[i].URL == "" || r.feeds[i].URL == post.FeedUrl {
+			r.feeds[i].Unread += delta
+		}
+	}
+}
+
+
+func (r *Reader) toggleStar() {
+	post := r.current()
+	if post == nil {
+		return
+	}
+	if r.starred[post.ID] {
+		_, err := r.db.UnstarPost(r.ctx, database.UnstarPostParams{
+			UserID: r.user.ID,
+			PostID: post.ID,
+		})
+		if err != nil {
+			r.status = fmt.Sprintf("error unstarring post: %v", err)
+			return
+		}
+		delete(r.starred, post.ID)
+		r.status = "Unstarred"
+		return
+	}
+	err := r.db.StarPost(r.ctx, database.StarPostParams{
+		UserID: r.user.ID,
+		PostID: post.ID,
+		Note: sql.NullString{},
+	})
+	if err != nil {
+		r.status = fmt.Sprintf("error starring post: %v", err)
+		return
+	}
+	r.starred[post.ID] = true
+	r.status = "Starred"
+}
+
+
+func (r *Reader) openLink() {
+	post := r.current()
+	if post == nil {
+		return
+	}
+	err := openURL(post.Url)
+	if err != nil {
+		r.status = err.Error()
+		return
+	}
+	r.status = "Opened " + post.Url
+}
+
+
+// current is the post actions apply to: the open one while reading it,
+// otherwise the selected one.
+func (r *Reader) current() *database.GetPostsForUserRow {
+	if r.focus == detailPane && r.open != nil {
+		return r.open
+	}
+	return r.selectedPost()
+}
+
+
+func (r *Reader) size() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 80, 24
+	}
+	return width, height
+}
+
+
+// decodeKey maps the bytes of one read from a raw terminal to a key.
+func decodeKey(input []byte) (key, rune) {
+	switch string(input) {
+	case "\x1b[A", "\x1bOA":
+		return keyUp, 0
+	case "\x1b[B", "\x1bOB":
+		return keyDown, 0
+	case "\x1b[C", "\x1bOC":
+		return keyRight, 0
+	case "\x1b[D", "\x1bOD":
+		return keyLeft, 0
+	case "\x1b[5~":
+		return keyPageUp, 0
+	case "\x1b[6~":
+		return keyPageDown, 0
+	case "\x1b[H", "\x1b[1~", "\x1bOH":
+		return keyHome, 0
+	case "\x1b[F", "\x1b[4~", "\x1bOF":
+		return keyEnd, 0
+	case "\x1b[Z":
+		return keyBackTab, 0
+	case "\t":
+		return keyTab, 0
+	case "\r", "\n":
+		return keyEnter, 0
+	case "\x1b":
+		return keyEscape, 0
+	}
+	if len(input) == 0 || input[0] == 0x1b {
+		return keyNone, 0
+	}
+	return keyRune, []rune(string(input))[0]
+}
+
+
+func clamp(value, low, high int) int {
+	if high < low {
+		return low
+	}
+	return max(low, min(value, high))
+}
@@ -0,0 +1,214 @@
+package reader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	styleReset = "\x1b[0m"
+	styleBold = "\x1b[1m"
+	styleDim = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+)
+
+const helpLine = "tab switch pane  j/k move  enter open  r read/unread  s star  o browser  R reload  q quit"
+
+// draw repaints the whole screen: feeds on the left, the post list above
+// the open post on the right and a status line at the bottom.
+func (r *Reader) draw() {
+	width, height := r.size()
+	feedsWidth := clamp(width/4, 12, 40)
+	rightWidth := max(width-feedsWidth-1, 1)
+	bodyHeight := max(height-1, 2)
+	postsHeight := clamp(bodyHeight/3, 3, bodyHeight-2)
+	detailHeight := bodyHeight - postsHeight - 1
+
+	left := r.feedLines(feedsWidth, bodyHeight)
+	right := r.postLines(rightWidth, postsHeight)
+	right = append(right, styleDim+strings.Repeat("─", rightWidth)+styleReset)
+	right = append(right, r.detailLines(rightWidth, detailHeight)...)
+
+	var frame strings.Builder
+	frame.WriteString("\x1b[H")
+	for row := 0; row < bodyHeight; row++ {
+		frame.WriteString(left[row])
+		frame.WriteString(styleDim + "│" + styleReset)
+		frame.WriteString(right[row])
+		frame.WriteString("\x1b[K\r\n")
+	}
+	status := r.status
+	if status == "" {
+		status = helpLine
+	}
+	frame.WriteString(cell(status, width, styleReverse))
+	os.Stdout.WriteString(frame.String())
+}
+
+
+func (r *Reader) feedLines(width, height int) []string {
+	lines := make([]string, 0, height)
+	lines = append(lines, r.header("Feeds", feedsPane, width))
+	top := scrollTop(r.feedIndex, 0, height-1, len(r.feeds))
+	for i := top; i < len(r.feeds) && len(lines) < height; i++ {
+		feed := r.feeds[i]
+		text := feed.Name
+		style := ""
+		if feed.Unread > 0 {
+			text = fmt.Sprintf("%s (%d)", feed.Name, feed.Unread)
+			style = styleBold
+		}
+		lines = append(lines, cell(" "+text, width, r.rowStyle(feedsPane, i == r.feedIndex, style)))
+	}
+	return fill(lines, width, height)
+}
+
+
+func (r *Reader) postLines(width, height int) []string {
+	lines := make([]string, 0, height)
+	lines = append(lines, r.header("Posts — "+r.feeds[r.feedIndex].Name, postsPane, width))
+	visible := r.visiblePosts()
+	if len(visible) == 0 {
+		lines = append(lines, cell(" No posts", width, styleDim))
+	}
+	r.postTop = scrollTop(r.postIndex, r.postTop, height-1, len(visible))
+	for i := r.postTop; i < len(visible) && len(lines) < height; i++ {
+		post := visible[i]
+		marker := " "
+		style := ""
+		if !post.Read {
+			marker = "●"
+			style = styleBold
+		}
+		star := " "
+		if r.starred[post.ID] {
+			star = "★"
+		}
+		date := "          "
+		if post.PublishedAt.Valid {
+			date = post.PublishedAt.Time.Format("2006-01-02")
+		}
+		text := fmt.Sprintf("%s%s %s %s", marker, star, date, post.Title)
+		if r.feeds[r.feedIndex].URL == "" {
+			text += "  · " + post.FeedName
+		}
+		lines = append(lines, cell(text, width, r.rowStyle(postsPane, i == r.postIndex, style)))
+	}
+	return fill(lines, width, height)
+}
+
+
+func (r *Reader) detailLines(width, height int) []string {
+	lines := make([]string, 0, height)
+	lines = append(lines, r.header("Post", detailPane, width))
+	if r.open == nil {
+		lines = append(lines, cell(" Press enter on a post to read it", width, styleDim))
+		return fill(lines, width, height)
+	}
+
+	post := r.open
+	textWidth := max(width-2, 1)
+	var body []bodyLine
+	addLines := func(text, style string) {
+		for _, line := range wrap(text, textWidth) {
+			body = append(body, bodyLine{text: line, style: style})
+		}
+	}
+	addLines(post.Title, styleBold)
+	meta := post.FeedName
+	if post.PublishedAt.Valid {
+		meta += " · " + post.PublishedAt.Time.Format("Mon, 02 Jan 2006 15:04")
+	}
+	addLines(meta, styleDim)
+	addLines(post.Url, styleDim)
+	body = append(body, bodyLine{})
+	// the full article when the feed carries one, otherwise the summary
+	text := post.Description.String
+	if post.Content.String != "" {
+		text = post.Content.String
+	}
+	addLines(HTMLToText(text), "")
+
+	r.detailTop = clamp(r.detailTop, 0, len(body)-(height-1))
+	for i := r.detailTop; i < len(body) && len(lines) < height; i++ {
+		lines = append(lines, cell(" "+body[i].text, width, body[i].style))
+	}
+	return fill(lines, width, height)
+}
+
+
+// bodyLine is a line of the open post. The style is kept apart from the
+// text, which comes from the feed and is never trusted to carry escapes.
+type bodyLine struct {
+	text string
+	style string
+}
+
+
+func (r *Reader) header(title string, p pane, width int) string {
+	style := styleDim
+	if r.focus == p {
+		style = styleBold
+	}
+	return cell(" "+title, width, style+"\x1b[4m")
+}
+
+
+func (r *Reader) rowStyle(p pane, selected bool, style string) string {
+	if !selected {
+		return style
+	}
+	if r.focus == p {
+		return style + styleReverse
+	}
+	return style + "\x1b[4m"
+}
+
+
+// cell pads or truncates text to exactly width columns and wraps it in
+// style. Control characters, C1 ones included, are blanked so that text
+// from feeds cannot send escape sequences to the terminal.
+func cell(text string, width int, style string) string {
+	text = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return ' '
+		}
+		return r
+	}, text)
+	length := utf8.RuneCountInString(text)
+	if length > width {
+		runes := []rune(text)
+		if width > 1 {
+			text = string(runes[:width-1]) + "…"
+		} else {
+			text = string(runes[:width])
+		}
+		length = width
+	}
+	return style + text + strings.Repeat(" ", width-length) + styleReset
+}
+
+
+func fill(lines []string, width, height int) []string {
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines[:height]
+}
+
+
+// scrollTop keeps the selected row inside a window of the given height.
+func scrollTop(selected, top, height, total int) int {
+	if height < 1 {
+		return selected
+	}
+	if selected < top {
+		top = selected
+	}
+	if selected >= top+height {
+		top = selected - height + 1
+	}
+	return clamp(top, 0, total-height)
+}
@@ -0,0 +1,52 @@
+package reader
+
+import (
+	"database/sql"
+	"gator/internal/database"
+	"strings"
+	"testing"
+)
+
+// escapes returns the escape sequences in line other than the reader's
+// own styles.
+func escapes(line string) []string {
+	for _, style := range []string{styleReset, styleBold, styleDim, styleReverse, "\x1b[4m"} {
+		line = strings.ReplaceAll(line, style, "")
+	}
+	var found []string
+	for _, r := range line {
+		if r == 0x1b || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			found = append(found, string(r))
+		}
+	}
+	return found
+}
+
+func TestCellBlanksControlCharacters(t *testing.T) {
+	for _, text := range []string{
+		"\x1b[31mred",
+		"\u009b31mred",
+		"del\x7f",
+		"\u0085next line",
+	} {
+		line := cell(text, 20, styleBold)
+		if found := escapes(line); len(found) > 0 {
+			t.Errorf("cell(%q) = %q, passes %q through", text, line, found)
+		}
+	}
+}
+
+func TestDetailLinesDoNotTrustPostText(t *testing.T) {
+	r := &Reader{
+		open: &database.GetPostsForUserRow{
+			Title: "\x1b[2J",
+			Url: "https://example.com/post",
+			Content: sql.NullString{String: "<p>\x1b[31mred</p><p>\u009b2Jclear</p>", Valid: true},
+		},
+	}
+	for _, line := range r.detailLines(40, 10) {
+		if found := escapes(line); len(found) > 0 {
+			t.Errorf("detail line %q passes %q through", line, found)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package sqlite implements database.Querier on a local SQLite file, with
+// queries equivalent to those in sql/queries. The schema is in
+// sql/sqlite/schema.
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"net/url"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Queries runs the gator queries against SQLite. Times are written in UTC
+// so that comparing them as text, as SQLite does, compares instants.
+type Queries struct {
+	db *sql.DB
+	now func() time.Time
+}
+
+var _ database.Querier = (*Queries)(nil)
+
+func New(db *sql.DB) *Queries {
+	return &Queries{
+		db: db,
+		now: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Open opens the SQLite database at path with foreign keys enforced, as
+// the ON DELETE CASCADE clauses of the schema rely on them.
+func Open(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_time_format", "sqlite")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil { return nil, fmt.Errorf("error opening SQLite database: %v", err) }
+	return db, nil
+}
+
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+const feedColumns = `id, created_at, updated_at, name, url, user_id, last_feteched, etag, last_modified, last_error, consecutive_failures, last_success_at, next_fetch_at, hinted_interval_seconds, interval_override_seconds, skip_hours, skip_days, adaptive_interval_seconds, site_url`
+
+func scanFeed(row scanner) (database.Feed, error) {
+	var i database.Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFeteched,
+		&i.Etag,
+		&i.LastModified,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LastSuccessAt,
+		&i.NextFetchAt,
+		&i.HintedIntervalSeconds,
+		&i.IntervalOverrideSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.AdaptiveIntervalSeconds,
+		&i.SiteUrl,
+	)
+	return i, err
+}
+
+
+// postColumns lists the columns of database.Post.
+const postColumns = `posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.content`
+
+func postTargets(i *database.Post) []any {
+	return []any{
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+	}
+}
+
+
+func utc(t time.Time) time.Time {
+	return t.UTC()
+}
+
+func nullUTC(t sql.NullTime) sql.NullTime {
+	if t.Valid {
+		t.Time = t.Time.UTC()
+	}
+	return t
+}
+
+
+// wrapConstraint reports unique constraint failures as
+// database.ErrUniqueViolation, which callers check for.
+func wrapConstraint(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return fmt.Errorf("%w: %v", database.ErrUniqueViolation, err)
+		}
+	}
+	return err
+}
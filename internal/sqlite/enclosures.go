@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const enclosureColumns = `enclosures.id, enclosures.created_at, enclosures.updated_at, enclosures.post_id, enclosures.url, enclosures.mime_type, enclosures.length, enclosures.duration_seconds, enclosures.episode, enclosures.image_url`
+
+const upsertEnclosure = `
+INSERT INTO enclosures (id, created_at, updated_at, post_id, url, mime_type, length, duration_seconds, episode, image_url)
+SELECT ?1, ?2, ?2, posts.id, ?3, ?4, ?5, ?6, ?7, ?8
+FROM posts
+WHERE posts.feed_id = ?9 AND posts.guid = ?10
+ON CONFLICT (post_id, url) DO UPDATE
+SET mime_type = excluded.mime_type,
+    length = excluded.length,
+    duration_seconds = excluded.duration_seconds,
+    episode = excluded.episode,
+    image_url = excluded.image_url,
+    updated_at = excluded.updated_at
+WHERE enclosures.mime_type IS NOT excluded.mime_type
+    OR enclosures.length IS NOT excluded.length
+    OR enclosures.duration_seconds IS NOT excluded.duration_seconds
+    OR enclosures.episode IS NOT excluded.episode
+    OR enclosures.image_url IS NOT excluded.image_url
+`
+
+func (q *Queries) UpsertEnclosure(ctx context.Context, arg database.UpsertEnclosureParams) error {
+	_, err := q.db.ExecContext(ctx, upsertEnclosure,
+		uuid.New(),
+		q.now(),
+		arg.Url,
+		arg.MimeType,
+		arg.Length,
+		arg.DurationSeconds,
+		arg.Episode,
+		arg.ImageUrl,
+		arg.FeedID,
+		arg.Guid,
+	)
+	return err
+}
+
+const getEnclosuresForPost = `
+SELECT ` + enclosureColumns + ` FROM enclosures
+WHERE post_id = ?1
+ORDER BY created_at, url
+`
+
+func (q *Queries) GetEnclosuresForPost(ctx context.Context, postID uuid.UUID) ([]database.Enclosure, error) {
+	rows, err := q.db.QueryContext(ctx, getEnclosuresForPost, postID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.Enclosure
+	for rows.Next() {
+		var i database.Enclosure
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PostID,
+			&i.Url,
+			&i.MimeType,
+			&i.Length,
+			&i.DurationSeconds,
+			&i.Episode,
+			&i.ImageUrl,
+		); err != nil { return nil, err }
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+const getPodcastEpisodesForUser = `
+SELECT
+    posts.id as post_id,
+    posts.title,
+    posts.published_at,
+    feeds.name as feed_name,
+    enclosures.url,
+    enclosures.mime_type,
+    enclosures.length,
+    enclosures.duration_seconds,
+    enclosures.episode,
+    COALESCE(post_states.read, false) as read
+FROM enclosures
+INNER JOIN posts on enclosures.post_id = posts.id
+INNER JOIN feeds on posts.feed_id = feeds.id
+INNER JOIN feed_follows on feed_follows.feed_id = feeds.id
+LEFT JOIN post_states on post_states.post_id = posts.id
+    AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = ?1
+    AND (enclosures.mime_type IS NULL
+        OR enclosures.mime_type LIKE 'audio/%'
+        OR enclosures.mime_type LIKE 'video/%')
+    AND (NOT ?2 OR post_states.read IS NOT 1)
+ORDER BY COALESCE(posts.published_at, posts.created_at) DESC, enclosures.created_at
+LIMIT ?3
+`
+
+func (q *Queries) GetPodcastEpisodesForUser(ctx context.Context, arg database.GetPodcastEpisodesForUserParams) ([]database.GetPodcastEpisodesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPodcastEpisodesForUser, arg.UserID, arg.UnreadOnly, arg.Limit)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.GetPodcastEpisodesForUserRow
+	for rows.Next() {
+		var i database.GetPodcastEpisodesForUserRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.Title,
+			&i.PublishedAt,
+			&i.FeedName,
+			&i.Url,
+			&i.MimeType,
+			&i.Length,
+			&i.DurationSeconds,
+			&i.Episode,
+			&i.Read,
+		); err != nil { return nil, err }
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,309 @@
+package sqlite
+
+import (
+	"context"
+	"gator/internal/database"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFeed = `
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+VALUES (?1, ?2, ?2, ?3, ?4, ?5)
+RETURNING ` + feedColumns
+
+func (q *Queries) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	row := q.db.QueryRowContext(ctx, createFeed, uuid.New(), q.now(), arg.Name, arg.Url, arg.UserID)
+	feed, err := scanFeed(row)
+	return feed, wrapConstraint(err)
+}
+
+const deleteFeed = `
+DELETE FROM feeds WHERE feeds.id = ?1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const getFeed = `
+SELECT ` + feedColumns + ` FROM feeds WHERE feeds.url = ?1
+`
+
+func (q *Queries) GetFeed(ctx context.Context, url string) (database.Feed, error) {
+	return scanFeed(q.db.QueryRowContext(ctx, getFeed, url))
+}
+
+const getFeeds = `
+SELECT
+    feeds.name,
+    feeds.url,
+    users.name as user_name
+FROM feeds
+INNER JOIN users ON feeds.user_id = users.id
+`
+
+func (q *Queries) GetFeeds(ctx context.Context) ([]database.GetFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeeds)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.GetFeedsRow
+	for rows.Next() {
+		var i database.GetFeedsRow
+		if err := rows.Scan(&i.Name, &i.Url, &i.UserName); err != nil { return nil, err }
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+const createFeedFollow = `
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (?1, ?2, ?2, ?3, ?4)
+`
+
+const getCreatedFeedFollow = `
+SELECT
+    feed_follows.id,
+    feed_follows.created_at,
+    feed_follows.updated_at,
+    feed_follows.user_id,
+    feed_follows.feed_id,
+    feed_follows.folder,
+    users.name as user_name,
+    feeds.name as feed_name
+FROM feed_follows
+JOIN users on feed_follows.user_id = users.id
+JOIN feeds on feed_follows.feed_id = feeds.id
+WHERE feed_follows.id = ?1
+`
+
+// CreateFeedFollow inserts then reads back the follow, as SQLite has no
+// data-modifying CTEs to do both in one statement.
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	var i database.CreateFeedFollowRow
+	id := uuid.New()
+	_, err := q.db.ExecContext(ctx, createFeedFollow, id, q.now(), arg.UserID, arg.FeedID)
+	if err != nil { return i, wrapConstraint(err) }
+	err = q.db.QueryRowContext(ctx, getCreatedFeedFollow, id).Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.Folder,
+		&i.UserName,
+		&i.FeedName,
+	)
+	return i, err
+}
+
+const getFeedFollowsForUser = `
+SELECT
+    users.name as user_name,
+    feeds.name as feed_name,
+    feeds.url as feed_url,
+    (
+        SELECT count(*)
+        FROM posts
+        LEFT JOIN post_states on post_states.post_id = posts.id
+            AND post_states.user_id = feed_follows.user_id
+        WHERE posts.feed_id = feeds.id AND post_states.read IS NOT 1
+    ) as unread_count
+FROM feed_follows
+JOIN users on feed_follows.user_id = users.id
+JOIN feeds on feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = ?1
+`
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.GetFeedFollowsForUserRow
+	for rows.Next() {
+		var i database.GetFeedFollowsForUserRow
+		if err := rows.Scan(&i.UserName, &i.FeedName, &i.FeedUrl, &i.UnreadCount); err != nil { return nil, err }
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+const setFeedFollowFolder = `
+UPDATE feed_follows
+SET folder = ?3, updated_at = ?4
+WHERE feed_follows.user_id = ?1 AND feed_follows.feed_id = ?2
+`
+
+func (q *Queries) SetFeedFollowFolder(ctx context.Context, arg database.SetFeedFollowFolderParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFollowFolder, arg.UserID, arg.FeedID, arg.Folder, q.now())
+	return err
+}
+
+const getFollowedFeedsForUser = `
+SELECT
+    feeds.name,
+    feeds.url,
+    feeds.site_url,
+    feed_follows.folder
+FROM feed_follows
+JOIN feeds on feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = ?1
+ORDER BY feed_follows.folder NULLS FIRST, feeds.name
+`
+
+func (q *Queries) GetFollowedFeedsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFollowedFeedsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedFeedsForUser, userID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.GetFollowedFeedsForUserRow
+	for rows.Next() {
+		var i database.GetFollowedFeedsForUserRow
+		if err := rows.Scan(&i.Name, &i.Url, &i.SiteUrl, &i.Folder); err != nil { return nil, err }
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+const unfollowFeed = `
+DELETE FROM feed_follows
+WHERE feed_follows.user_id = ?1 AND feed_follows.feed_id = ?2
+`
+
+func (q *Queries) UnfollowFeed(ctx context.Context, arg database.UnfollowFeedParams) error {
+	_, err := q.db.ExecContext(ctx, unfollowFeed, arg.UserID, arg.FeedID)
+	return err
+}
+
+// claimNextFeedToFetch needs no row locking: SQLite runs one write at a
+// time, and the lease in ?2 keeps the claimed feed from being due again.
+const claimNextFeedToFetch = `
+UPDATE feeds
+SET last_feteched = ?1, next_fetch_at = ?2, updated_at = ?1
+WHERE feeds.id = (
+    SELECT id
+    FROM feeds
+    WHERE next_fetch_at IS NULL OR next_fetch_at <= ?1
+    ORDER BY last_feteched NULLS FIRST
+    LIMIT 1
+)
+RETURNING ` + feedColumns
+
+func (q *Queries) ClaimNextFeedToFetch(ctx context.Context, leaseSeconds int32) (database.Feed, error) {
+	now := q.now()
+	lease := time.Duration(leaseSeconds) * time.Second
+	return scanFeed(q.db.QueryRowContext(ctx, claimNextFeedToFetch, now, now.Add(lease)))
+}
+
+const updateFeedCache = `
+UPDATE feeds
+SET etag = ?2, last_modified = ?3, updated_at = ?4
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) UpdateFeedCache(ctx context.Context, arg database.UpdateFeedCacheParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCache, arg.ID, arg.Etag, arg.LastModified, q.now())
+	return err
+}
+
+const recordFeedSuccess = `
+UPDATE feeds
+SET last_error = NULL,
+    consecutive_failures = 0,
+    last_success_at = ?3,
+    next_fetch_at = ?2,
+    updated_at = ?3
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, arg database.RecordFeedSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedSuccess, arg.ID, nullUTC(arg.NextFetchAt), q.now())
+	return err
+}
+
+const recordFeedFailure = `
+UPDATE feeds
+SET last_error = ?2,
+    consecutive_failures = consecutive_failures + 1,
+    next_fetch_at = ?3,
+    updated_at = ?4
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) RecordFeedFailure(ctx context.Context, arg database.RecordFeedFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedFailure, arg.ID, arg.LastError, nullUTC(arg.NextFetchAt), q.now())
+	return err
+}
+
+const getFailingFeeds = `
+SELECT ` + feedColumns + ` FROM feeds
+WHERE consecutive_failures > 0
+ORDER BY consecutive_failures DESC, name
+`
+
+func (q *Queries) GetFailingFeeds(ctx context.Context) ([]database.Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFailingFeeds)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.Feed
+	for rows.Next() {
+		feed, err := scanFeed(rows)
+		if err != nil { return nil, err }
+		items = append(items, feed)
+	}
+	return items, rows.Err()
+}
+
+const updateFeedSchedule = `
+UPDATE feeds
+SET hinted_interval_seconds = ?2,
+    skip_hours = ?3,
+    skip_days = ?4,
+    updated_at = ?5
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) UpdateFeedSchedule(ctx context.Context, arg database.UpdateFeedScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedSchedule,
+		arg.ID,
+		arg.HintedIntervalSeconds,
+		arg.SkipHours,
+		arg.SkipDays,
+		q.now(),
+	)
+	return err
+}
+
+const setFeedIntervalOverride = `
+UPDATE feeds
+SET interval_override_seconds = ?2, updated_at = ?3
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) SetFeedIntervalOverride(ctx context.Context, arg database.SetFeedIntervalOverrideParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedIntervalOverride, arg.ID, arg.IntervalOverrideSeconds, q.now())
+	return err
+}
+
+const setFeedAdaptiveInterval = `
+UPDATE feeds
+SET adaptive_interval_seconds = ?2, updated_at = ?3
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) SetFeedAdaptiveInterval(ctx context.Context, arg database.SetFeedAdaptiveIntervalParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedAdaptiveInterval, arg.ID, arg.AdaptiveIntervalSeconds, q.now())
+	return err
+}
+
+const setFeedSiteURL = `
+UPDATE feeds
+SET site_url = ?2, updated_at = ?3
+WHERE feeds.id = ?1
+`
+
+func (q *Queries) SetFeedSiteURL(ctx context.Context, arg database.SetFeedSiteURLParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedSiteURL, arg.ID, arg.SiteUrl, q.now())
+	return err
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gator/internal/database"
+	schema "gator/sql/sqlite/schema"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+func TestClaimNextFeedToFetchLeasesTheFeed(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil { t.Fatal(err) }
+	defer db.Close()
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil { t.Fatal(err) }
+	_, err = provider.Up(ctx)
+	if err != nil { t.Fatalf("migrating up: %v", err) }
+
+	q := New(db)
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+	user, err := q.CreateUser(ctx, "alice")
+	if err != nil { t.Fatal(err) }
+	_, err = q.CreateFeed(ctx, database.CreateFeedParams{Name: "Example", Url: "https://example.com/feed", UserID: user.ID})
+	if err != nil { t.Fatal(err) }
+
+	feed, err := q.ClaimNextFeedToFetch(ctx, 90)
+	if err != nil { t.Fatalf("ClaimNextFeedToFetch: %v", err) }
+	if want := now.Add(90 * time.Second); !feed.NextFetchAt.Valid || !feed.NextFetchAt.Time.Equal(want) {
+		t.Errorf("next_fetch_at = %v, want the lease to end at %v", feed.NextFetchAt, want)
+	}
+
+	_, err = q.ClaimNextFeedToFetch(ctx, 90)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("claiming during the lease = %v, want no feed", err)
+	}
+	now = now.Add(90 * time.Second)
+	_, err = q.ClaimNextFeedToFetch(ctx, 90)
+	if err != nil {
+		t.Errorf("claiming after the lease: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const starPost = `
+INSERT INTO post_stars (user_id, post_id, created_at, updated_at, note)
+VALUES (?1, ?2, ?4, ?4, ?3)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET note = excluded.note, updated_at = ?4
+`
+
+func (q *Queries) StarPost(ctx context.Context, arg database.StarPostParams) error {
+	_, err := q.db.ExecContext(ctx, starPost, arg.UserID, arg.PostID, arg.Note, q.now())
+	return err
+}
+
+const unstarPost = `
+DELETE FROM post_stars
+WHERE post_stars.user_id = ?1 AND post_stars.post_id = ?2
+`
+
+func (q *Queries) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unstarPost, arg.UserID, arg.PostID)
+	if err != nil { return 0, err }
+	return result.RowsAffected()
+}
+
+const getStarredPostsForUser = `
+SELECT
+    ` + postColumns + `,
+    feeds.name as feed_name,
+    post_stars.note,
+    post_stars.created_at as starred_at
+FROM post_stars
+INNER JOIN posts on post_stars.post_id = posts.id
+INNER JOIN feeds on posts.feed_id = feeds.id
+WHERE post_stars.user_id = ?1
+ORDER BY post_stars.created_at DESC
+`
+
+func (q *Queries) GetStarredPostsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStarredPostsForUser, userID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.GetStarredPostsForUserRow
+	for rows.Next() {
+		var i database.GetStarredPostsForUserRow
+		var post database.Post
+		targets := append(postTargets(&post), &i.FeedName, &i.Note, &i.StarredAt)
+		if err := rows.Scan(targets...); err != nil { return nil, err }
+		i.ID = post.ID
+		i.CreatedAt = post.CreatedAt
+		i.UpdatedAt = post.UpdatedAt
+		i.Title = post.Title
+		i.Url = post.Url
+		i.Description = post.Description
+		i.PublishedAt = post.PublishedAt
+		i.FeedID = post.FeedID
+		i.Guid = post.Guid
+		i.Content = post.Content
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"gator/internal/database"
+)
+
+const markPostRead = `
+INSERT INTO post_states (user_id, post_id, created_at, updated_at, read, read_at)
+VALUES (?1, ?2, ?3, ?3, true, ?3)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, read_at = ?3, updated_at = ?3
+`
+
+func (q *Queries) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostRead, arg.UserID, arg.PostID, q.now())
+	return err
+}
+
+const markPostUnread = `
+INSERT INTO post_states (user_id, post_id, created_at, updated_at, read, read_at)
+VALUES (?1, ?2, ?3, ?3, false, NULL)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = false, read_at = NULL, updated_at = ?3
+`
+
+func (q *Queries) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostUnread, arg.UserID, arg.PostID, q.now())
+	return err
+}
+
+const markFeedRead = `
+INSERT INTO post_states (user_id, post_id, created_at, updated_at, read, read_at)
+SELECT ?1, posts.id, ?3, ?3, true, ?3
+FROM posts
+WHERE posts.feed_id = ?2
+    AND EXISTS (
+        SELECT 1 FROM feed_follows
+        WHERE feed_follows.user_id = ?1 AND feed_follows.feed_id = posts.feed_id
+    )
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, read_at = ?3, updated_at = ?3
+WHERE post_states.read = false
+`
+
+func (q *Queries) MarkFeedRead(ctx context.Context, arg database.MarkFeedReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markFeedRead, arg.UserID, arg.FeedID, q.now())
+	if err != nil { return 0, err }
+	return result.RowsAffected()
+}
+
+const markPostsReadBefore = `
+INSERT INTO post_states (user_id, post_id, created_at, updated_at, read, read_at)
+SELECT ?1, posts.id, ?3, ?3, true, ?3
+FROM posts
+INNER JOIN feed_follows on feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = ?1
+    AND COALESCE(posts.published_at, posts.created_at) < ?2
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, read_at = ?3, updated_at = ?3
+WHERE post_states.read = false
+`
+
+func (q *Queries) MarkPostsReadBefore(ctx context.Context, arg database.MarkPostsReadBeforeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostsReadBefore, arg.UserID, utc(arg.Before), q.now())
+	if err != nil { return 0, err }
+	return result.RowsAffected()
+}
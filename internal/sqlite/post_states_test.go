@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	schema "gator/sql/sqlite/schema"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+)
+
+func TestMarkFeedReadNeedsAFollow(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil { t.Fatal(err) }
+	defer db.Close()
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil { t.Fatal(err) }
+	_, err = provider.Up(ctx)
+	if err != nil { t.Fatalf("migrating up: %v", err) }
+
+	q := New(db)
+	alice, err := q.CreateUser(ctx, "alice")
+	if err != nil { t.Fatal(err) }
+	bob, err := q.CreateUser(ctx, "bob")
+	if err != nil { t.Fatal(err) }
+	feed, err := q.CreateFeed(ctx, database.CreateFeedParams{Name: "Example", Url: "https://example.com/feed", UserID: alice.ID})
+	if err != nil { t.Fatal(err) }
+	_, err = q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{UserID: alice.ID, FeedID: feed.ID})
+	if err != nil { t.Fatal(err) }
+	_, err = q.UpsertPost(ctx, database.UpsertPostParams{
+		Title: "First post",
+		Url: "https://example.com/first",
+		Description: sql.NullString{String: "Hello", Valid: true},
+		FeedID: feed.ID,
+		Guid: "https://example.com/first",
+	})
+	if err != nil { t.Fatalf("UpsertPost: %v", err) }
+
+	count, err := q.MarkFeedRead(ctx, database.MarkFeedReadParams{UserID: bob.ID, FeedID: feed.ID})
+	if err != nil { t.Fatalf("MarkFeedRead: %v", err) }
+	if count != 0 {
+		t.Errorf("marked %d posts read for a feed bob doesn't follow, want 0", count)
+	}
+	count, err = q.MarkFeedRead(ctx, database.MarkFeedReadParams{UserID: alice.ID, FeedID: feed.ID})
+	if err != nil { t.Fatalf("MarkFeedRead: %v", err) }
+	if count != 1 {
+		t.Errorf("marked %d posts read for alice, want 1", count)
+	}
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getPostsForUser = `
+SELECT
+    ` + postColumns + `,
+    feeds.name as feed_name,
+    feeds.url as feed_url,
+    COALESCE(post_states.read, false) as read
+FROM posts
+INNER JOIN feeds on posts.feed_id = feeds.id
+INNER JOIN feed_follows on feed_follows.feed_id = feeds.id
+LEFT JOIN post_states on post_states.post_id = posts.id
+    AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = ?1
+    AND (NOT ?2 OR post_states.read IS NOT 1)
+ORDER BY COALESCE(posts.published_at, posts.created_at) DESC
+LIMIT ?3
+`
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.UnreadOnly, arg.Limit)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.GetPostsForUserRow
+	for rows.Next() {
+		var i database.GetPostsForUserRow
+		var post database.Post
+		targets := append(postTargets(&post), &i.FeedName, &i.FeedUrl, &i.Read)
+		if err := rows.Scan(targets...); err != nil { return nil, err }
+		i.ID = post.ID
+		i.CreatedAt = post.CreatedAt
+		i.UpdatedAt = post.UpdatedAt
+		i.Title = post.Title
+		i.Url = post.Url
+		i.Description = post.Description
+		i.PublishedAt = post.PublishedAt
+		i.FeedID = post.FeedID
+		i.Guid = post.Guid
+		i.Content = post.Content
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+const getFeedPublishDates = `
+SELECT published_at
+FROM posts
+WHERE feed_id = ?1 AND published_at IS NOT NULL
+ORDER BY published_at DESC
+LIMIT ?2
+`
+
+func (q *Queries) GetFeedPublishDates(ctx context.Context, arg database.GetFeedPublishDatesParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedPublishDates, arg.FeedID, arg.Limit)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var published_at sql.NullTime
+		if err := rows.Scan(&published_at); err != nil { return nil, err }
+		items = append(items, published_at)
+	}
+	return items, rows.Err()
+}
+
+const deletePostsOlderThan = `
+DELETE FROM posts
+WHERE COALESCE(posts.published_at, posts.created_at) < ?1
+    AND NOT EXISTS (
+        SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id
+    )
+`
+
+func (q *Queries) DeletePostsOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePostsOlderThan, utc(before))
+	if err != nil { return 0, err }
+	return result.RowsAffected()
+}
+
+const adoptLegacyPost = `
+UPDATE posts
+SET guid = ?1
+WHERE posts.feed_id = ?2
+    AND posts.url = ?3
+    AND posts.guid = posts.url
+    AND NOT EXISTS (
+        SELECT 1 FROM posts existing
+        WHERE existing.feed_id = ?2 AND existing.guid = ?1
+    )
+`
+
+func (q *Queries) AdoptLegacyPost(ctx context.Context, arg database.AdoptLegacyPostParams) error {
+	_, err := q.db.ExecContext(ctx, adoptLegacyPost, arg.Guid, arg.FeedID, arg.Url)
+	return err
+}
+
+// upsertPost compares with IS NOT, SQLite's IS DISTINCT FROM.
+const upsertPost = `
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content)
+VALUES (?1, ?2, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)
+ON CONFLICT (feed_id, guid) DO UPDATE
+SET title = excluded.title,
+    url = excluded.url,
+    description = excluded.description,
+    content = excluded.content,
+    published_at = COALESCE(excluded.published_at, posts.published_at),
+    updated_at = excluded.updated_at
+WHERE posts.title IS NOT excluded.title
+    OR posts.url IS NOT excluded.url
+    OR posts.description IS NOT excluded.description
+    OR posts.content IS NOT excluded.content
+RETURNING ` + postColumns
+
+func (q *Queries) UpsertPost(ctx context.Context, arg database.UpsertPostParams) (database.Post, error) {
+	row := q.db.QueryRowContext(ctx, upsertPost,
+		uuid.New(),
+		q.now(),
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		nullUTC(arg.PublishedAt),
+		arg.FeedID,
+		arg.Guid,
+		arg.Content,
+	)
+	var i database.Post
+	err := row.Scan(postTargets(&i)...)
+	return i, wrapConstraint(err)
+}
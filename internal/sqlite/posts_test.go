@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gator/internal/database"
+	schema "gator/sql/sqlite/schema"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
+)
+
+// TestAdoptLegacyPost stores a post under the schema from before guids,
+// migrates, and checks that fetching its item again updates that post
+// instead of adding a second one.
+func TestAdoptLegacyPost(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil { t.Fatal(err) }
+	defer db.Close()
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil { t.Fatal(err) }
+	_, err = provider.UpTo(ctx, 1)
+	if err != nil { t.Fatalf("migrating to version 1: %v", err) }
+
+	userID, feedID, postID := uuid.New(), uuid.New(), uuid.New()
+	link := "https://example.com/first?utm_source=rss"
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (?1, 'alice')`, userID)
+	if err != nil { t.Fatal(err) }
+	_, err = db.ExecContext(ctx, `INSERT INTO feeds (id, name, url, user_id) VALUES (?1, 'Example', 'https://example.com/feed', ?2)`, feedID, userID)
+	if err != nil { t.Fatal(err) }
+	_, err = db.ExecContext(ctx, `INSERT INTO posts (id, title, url, description, feed_id) VALUES (?1, 'First post', ?2, 'Hello', ?3)`, postID, link, feedID)
+	if err != nil { t.Fatal(err) }
+
+	_, err = provider.Up(ctx)
+	if err != nil { t.Fatalf("migrating up: %v", err) }
+
+	q := New(db)
+	params := database.AdoptLegacyPostParams{Guid: "tag:example.com,2026:1", FeedID: feedID, Url: link}
+	err = q.AdoptLegacyPost(ctx, params)
+	if err != nil { t.Fatalf("AdoptLegacyPost: %v", err) }
+	_, err = q.UpsertPost(ctx, database.UpsertPostParams{
+		Title: "First post",
+		Url: link,
+		Description: sql.NullString{String: "Hello", Valid: true},
+		FeedID: feedID,
+		Guid: params.Guid,
+	})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("UpsertPost of the adopted post = %v, want it unchanged", err)
+	}
+
+	var count int
+	var guid string
+	err = db.QueryRowContext(ctx, `SELECT count(*), max(guid) FROM posts WHERE id = ?1`, postID).Scan(&count, &guid)
+	if err != nil { t.Fatal(err) }
+	if count != 1 || guid != params.Guid {
+		t.Errorf("stored post has guid %q (%d rows), want %q", guid, count, params.Guid)
+	}
+	err = db.QueryRowContext(ctx, `SELECT count(*) FROM posts`).Scan(&count)
+	if err != nil { t.Fatal(err) }
+	if count != 1 {
+		t.Errorf("got %d posts, want 1", count)
+	}
+
+	// a post that already has the guid is never displaced
+	err = q.AdoptLegacyPost(ctx, database.AdoptLegacyPostParams{Guid: params.Guid, FeedID: feedID, Url: "https://example.com/other"})
+	if err != nil { t.Fatalf("AdoptLegacyPost: %v", err) }
+	err = db.QueryRowContext(ctx, `SELECT count(*) FROM posts WHERE guid = ?1`, params.Guid).Scan(&count)
+	if err != nil { t.Fatal(err) }
+	if count != 1 {
+		t.Errorf("%d posts have the guid, want 1", count)
+	}
+}
+
+func TestGetPostsForUserDatesUndatedPostsWhenStored(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil { t.Fatal(err) }
+	defer db.Close()
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil { t.Fatal(err) }
+	_, err = provider.Up(ctx)
+	if err != nil { t.Fatalf("migrating up: %v", err) }
+
+	q := New(db)
+	q.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	user, err := q.CreateUser(ctx, "alice")
+	if err != nil { t.Fatal(err) }
+	feed, err := q.CreateFeed(ctx, database.CreateFeedParams{Name: "Example", Url: "https://example.com/feed", UserID: user.ID})
+	if err != nil { t.Fatal(err) }
+	_, err = q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{UserID: user.ID, FeedID: feed.ID})
+	if err != nil { t.Fatal(err) }
+	for _, params := range []database.UpsertPostParams{
+		{Title: "Undated", Url: "https://example.com/undated", Guid: "undated"},
+		{
+			Title: "Dated",
+			Url: "https://example.com/dated",
+			Guid: "dated",
+			PublishedAt: sql.NullTime{Time: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+		},
+	} {
+		params.FeedID = feed.ID
+		_, err = q.UpsertPost(ctx, params)
+		if err != nil { t.Fatalf("UpsertPost: %v", err) }
+	}
+
+	posts, err := q.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, Limit: 10})
+	if err != nil { t.Fatalf("GetPostsForUser: %v", err) }
+	if len(posts) != 2 || posts[0].Title != "Dated" {
+		t.Errorf("posts = %+v, want Dated before the older undated post", posts)
+	}
+}
+
+// TestPostGuidDownKeepsStars rolls back the guid migration over two posts
+// sharing a url and checks that the star and read state on the older one
+// move to the post that is kept.
+func TestPostGuidDownKeepsStars(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil { t.Fatal(err) }
+	defer db.Close()
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, schema.FS)
+	if err != nil { t.Fatal(err) }
+	_, err = provider.UpTo(ctx, 2)
+	if err != nil { t.Fatalf("migrating to version 2: %v", err) }
+
+	userID, feedID, olderID, newerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	for _, statement := range []struct {
+		query string
+		args []any
+	}{
+		{`INSERT INTO users (id, name) VALUES (?1, 'alice')`, []any{userID}},
+		{`INSERT INTO feeds (id, name, url, user_id) VALUES (?1, 'Example', 'https://example.com/feed', ?2)`, []any{feedID, userID}},
+		{`INSERT INTO posts (id, created_at, title, url, feed_id, guid) VALUES (?1, '2026-01-01 00:00:00', 'Old', 'https://example.com/1', ?2, 'a')`, []any{olderID, feedID}},
+		{`INSERT INTO posts (id, created_at, title, url, feed_id, guid) VALUES (?1, '2026-01-02 00:00:00', 'New', 'https://example.com/1', ?2, 'b')`, []any{newerID, feedID}},
+		{`INSERT INTO post_stars (user_id, post_id, note) VALUES (?1, ?2, 'keep me')`, []any{userID, olderID}},
+		{`INSERT INTO post_states (user_id, post_id, read) VALUES (?1, ?2, true)`, []any{userID, olderID}},
+	} {
+		_, err = db.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil { t.Fatal(err) }
+	}
+
+	_, err = provider.DownTo(ctx, 1)
+	if err != nil { t.Fatalf("migrating down to version 1: %v", err) }
+
+	var postID, note string
+	var read bool
+	err = db.QueryRowContext(ctx, `SELECT id FROM posts`).Scan(&postID)
+	if err != nil { t.Fatal(err) }
+	if postID != newerID.String() {
+		t.Errorf("kept post %s, want the newer %s", postID, newerID)
+	}
+	err = db.QueryRowContext(ctx, `SELECT note FROM post_stars WHERE post_id = ?1`, postID).Scan(&note)
+	if err != nil { t.Fatalf("star of the kept post: %v", err) }
+	if note != "keep me" {
+		t.Errorf("note = %q, want the note of the merged post", note)
+	}
+	err = db.QueryRowContext(ctx, `SELECT read FROM post_states WHERE post_id = ?1`, postID).Scan(&read)
+	if err != nil { t.Fatalf("read state of the kept post: %v", err) }
+	if !read {
+		t.Error("kept post lost the read state of the merged post")
+	}
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"gator/internal/database"
+	"gator/internal/websearch"
+	"strings"
+	"unicode"
+)
+
+// searchPosts ranks with bm25, weighting the title like the 'A' and the
+// description like the 'B' weight of the PostgreSQL post_search_vector.
+const searchPosts = `
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.published_at,
+    feeds.name as feed_name,
+    -bm25(posts_fts, 1.0, 0.4) as rank,
+    snippet(posts_fts, -1, '**', '**', '…', 35) as snippet
+FROM posts_fts
+INNER JOIN posts on posts.seq = posts_fts.rowid
+INNER JOIN feeds on posts.feed_id = feeds.id
+WHERE posts_fts MATCH ?1
+    AND (
+        ?2
+        OR EXISTS (
+            SELECT 1 FROM feed_follows
+            WHERE feed_follows.feed_id = posts.feed_id
+                AND feed_follows.user_id = ?3
+        )
+    )
+ORDER BY rank DESC, COALESCE(posts.published_at, posts.created_at) DESC
+LIMIT ?4
+`
+
+func (q *Queries) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	match := ftsQuery(arg.Query)
+	if match == "" {
+		return nil, nil
+	}
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		match,
+		arg.AllFeeds,
+		arg.UserID,
+		arg.Limit,
+	)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []database.SearchPostsRow
+	for rows.Next() {
+		var i database.SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil { return nil, err }
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+
+// ftsQuery translates the web search syntax accepted by PostgreSQL's
+// websearch_to_tsquery ("quoted phrases", OR and -excluded) to an FTS5
+// query. Every term is quoted so no input is an FTS5 syntax error.
+func ftsQuery(query string) string {
+	var groups []string
+	for _, terms := range websearch.Parse(query) {
+		var positive, negative []string
+		for _, t := range terms {
+			if strings.IndexFunc(t.Text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+				continue
+			}
+			term := `"` + strings.ReplaceAll(t.Text, `"`, `""`) + `"`
+			if t.Negated {
+				negative = append(negative, term)
+			} else {
+				positive = append(positive, term)
+			}
+		}
+		if len(positive) > 0 {
+			group := strings.Join(positive, " AND ")
+			for _, term := range negative {
+				group += " NOT " + term
+			}
+			groups = append(groups, "("+group+")")
+		}
+	}
+	return strings.Join(groups, " OR ")
+}
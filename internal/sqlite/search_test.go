@@ -0,0 +1,20 @@
+package sqlite
+
+import "testing"
+
+func TestFTSQuery(t *testing.T) {
+	tests := map[string]string{
+		"": "",
+		"go rss": `("go" AND "rss")`,
+		`"feed reader" -atom`: `("feed reader" NOT "atom")`,
+		"go OR rust": `("go") OR ("rust")`,
+		"-only -negated": "",
+		`NEAR( * "`: `("NEAR(")`,
+		`say "hi"there`: `("say" AND "hi""there")`,
+	}
+	for query, want := range tests {
+		if got := ftsQuery(query); got != want {
+			t.Errorf("ftsQuery(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
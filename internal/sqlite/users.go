@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const createUser = `
+INSERT INTO users (id, created_at, updated_at, name)
+VALUES (?1, ?2, ?2, ?3)
+RETURNING id, created_at, updated_at, name
+`
+
+func (q *Queries) CreateUser(ctx context.Context, name string) (database.User, error) {
+	row := q.db.QueryRowContext(ctx, createUser, uuid.New(), q.now(), name)
+	var i database.User
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt, &i.Name)
+	return i, wrapConstraint(err)
+}
+
+const getUser = `
+SELECT id, created_at, updated_at, name FROM users WHERE users.name = ?1
+`
+
+func (q *Queries) GetUser(ctx context.Context, name string) (database.User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, name)
+	var i database.User
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt, &i.Name)
+	return i, err
+}
+
+const getUsers = `
+SELECT name FROM users
+`
+
+func (q *Queries) GetUsers(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil { return nil, err }
+		items = append(items, name)
+	}
+	return items, rows.Err()
+}
+
+const truncateUsers = `
+DELETE FROM users
+`
+
+func (q *Queries) TruncateUsers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, truncateUsers)
+	return err
+}
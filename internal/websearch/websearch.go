@@ -0,0 +1,67 @@
+// Package websearch parses the web search syntax of PostgreSQL's
+// websearch_to_tsquery, for the stores that implement search themselves:
+// words, "quoted phrases", OR between alternatives and -excluded terms.
+package websearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Term is one search term: a word, or the text of a quoted phrase.
+type Term struct {
+	Text string
+	Negated bool
+}
+
+// Parse splits query into groups separated by OR. A document matches the
+// query when it matches any group, that is has all the group's terms that
+// are not negated and none of those that are.
+func Parse(query string) [][]Term {
+	var groups [][]Term
+	var group []Term
+	for _, field := range split(query) {
+		if strings.EqualFold(field, "or") {
+			if len(group) > 0 {
+				groups = append(groups, group)
+				group = nil
+			}
+			continue
+		}
+		negated := strings.HasPrefix(field, "-")
+		text := strings.Trim(strings.TrimPrefix(field, "-"), `"`)
+		if text != "" {
+			group = append(group, Term{Text: text, Negated: negated})
+		}
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+
+// split splits on spaces outside double quotes.
+func split(text string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			field.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
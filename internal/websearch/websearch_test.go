@@ -0,0 +1,26 @@
+package websearch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want [][]Term
+	}{
+		{"", nil},
+		{"go rss", [][]Term{{{Text: "go"}, {Text: "rss"}}}},
+		{`"feed reader" -atom`, [][]Term{{{Text: "feed reader"}, {Text: "atom", Negated: true}}}},
+		{"go OR rust or zig", [][]Term{{{Text: "go"}}, {{Text: "rust"}}, {{Text: "zig"}}}},
+		{"or go OR OR rust or", [][]Term{{{Text: "go"}}, {{Text: "rust"}}}},
+		{`-"" "unterminated phrase`, [][]Term{{{Text: "unterminated phrase"}}}},
+		{"  spaced\tout\n", [][]Term{{{Text: "spaced"}, {Text: "out"}}}},
+	}
+	for _, test := range tests {
+		if got := Parse(test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", test.query, got, test.want)
+		}
+	}
+}
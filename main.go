@@ -5,10 +5,13 @@ import _ "github.com/lib/pq"
 import (
 	"fmt"
 	"os"
+	"strings"
 	"database/sql"
 	"gator/internal/database"
 	"gator/internal/config"
 	"gator/internal/cmd"
+	"gator/internal/migrate"
+	"gator/internal/sqlite"
 )
 
 
@@ -18,41 +21,43 @@ func main() {
 	myConfig, err := config.Read()
 	if err != nil { panic(err) }
 	
-	db, err := sql.Open("postgres", myConfig.DBUrl)
+	db, dbQueries, dialect, err := openDB(myConfig.DBUrl)
 	if err != nil { panic(err) }
 	defer db.Close()
 
-	dbQueries := database.New(db)
+	migrator, err := migrate.New(db, dialect)
+	if err != nil { panic(err) }
+
 	state := cmd.State{
 		Config: myConfig,
 		DB: dbQueries,
+		Migrator: migrator,
 	}
 
-	commands := cmd.Commands{
-		Commands: map[string]func(*cmd.State, cmd.Command) error{
-			"login": cmd.HandlerLogin,
-			"register": cmd.HandlerRegister,
-			"reset": cmd.HandlerReset,
-			"users": cmd.HandlerListUsers,
-			"agg": cmd.HandlerAgg,
-			"addfeed": cmd.MiddlewareLoggedIn(cmd.HandlerAddFeed),
-			"feeds": cmd.HandlerListFeeds,
-			"follow": cmd.MiddlewareLoggedIn(cmd.HandlerFollow),
-			"following": cmd.MiddlewareLoggedIn(cmd.HandlerListUserFollows),
-			"unfollow": cmd.MiddlewareLoggedIn(cmd.HandlerUnfollow),
-			"browse": cmd.MiddlewareLoggedIn(cmd.HandlerBrowse),
-		},
-	}
-	args := make([]string,0)
-	args = append(args, os.Args[1:]...)
-	if len(args) < 1 {
-		fmt.Printf("You need to provide at least one command and an argument")
+	commands := cmd.NewCommands()
+	command := cmd.NewCommand(os.Args[1:])
+	if command.Name == "" {
+		commands.PrintUsage(os.Stderr)
 		os.Exit(1)
 	}
-	err = commands.Run(&state, cmd.Command{Name: args[0], Arguments: args[1:]})
+	err = commands.Run(&state, command)
 	if err != nil { 
-		fmt.Printf("error: %v", err)
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("Done\n")
+}
+
+// openDB picks the storage backend from the db_url scheme: sqlite:path
+// (or sqlite://path) opens a SQLite file, anything else is PostgreSQL.
+func openDB(dbURL string) (*sql.DB, database.Querier, migrate.Dialect, error) {
+	if path, ok := strings.CutPrefix(dbURL, "sqlite:"); ok {
+		path = strings.TrimPrefix(path, "//")
+		if path == "" { return nil, nil, "", fmt.Errorf("db_url %q has no SQLite file path", dbURL) }
+		db, err := sqlite.Open(path)
+		if err != nil { return nil, nil, "", err }
+		return db, sqlite.New(db), migrate.SQLite, nil
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil { return nil, nil, "", err }
+	return db, database.New(db), migrate.Postgres, nil
 }
@@ -0,0 +1,134 @@
+// Package opml reads and writes OPML subscription lists.
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+	OwnerName   string `xml:"ownerName,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is either a subscription, when XMLURL is set, or a folder
+// grouping the outlines nested in it.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Subscription is a feed outline together with the folder it was found
+// in, as a slash separated path of folder names.
+type Subscription struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	Folder  string
+}
+
+func Parse(r io.Reader) (*OPML, error) {
+	doc := OPML{}
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil { return nil, fmt.Errorf("error parsing OPML: %v", err) }
+	return &doc, nil
+}
+
+// Subscriptions flattens the outline tree into the feeds it contains.
+func (doc *OPML) Subscriptions() []Subscription {
+	var subscriptions []Subscription
+	collect(doc.Body.Outlines, nil, &subscriptions)
+	return subscriptions
+}
+
+func collect(outlines []Outline, folders []string, subscriptions *[]Subscription) {
+	for _, outline := range outlines {
+		name := strings.TrimSpace(outline.Title)
+		if name == "" {
+			name = strings.TrimSpace(outline.Text)
+		}
+		if outline.XMLURL == "" {
+			collect(outline.Outlines, append(folders, name), subscriptions)
+			continue
+		}
+		if name == "" {
+			name = outline.XMLURL
+		}
+		*subscriptions = append(*subscriptions, Subscription{
+			Title:   name,
+			XMLURL:  strings.TrimSpace(outline.XMLURL),
+			HTMLURL: strings.TrimSpace(outline.HTMLURL),
+			Folder:  strings.Join(folders, "/"),
+		})
+	}
+}
+
+// New builds an OPML 2.0 document from subscriptions, nesting them in
+// folder outlines by their folder path. Order is kept within each folder.
+func New(title string, dateCreated time.Time, subscriptions []Subscription) *OPML {
+	doc := &OPML{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: dateCreated.Format(time.RFC1123Z),
+		},
+	}
+	for _, subscription := range subscriptions {
+		outlines := &doc.Body.Outlines
+		if subscription.Folder != "" {
+			for _, folder := range strings.Split(subscription.Folder, "/") {
+				outlines = &folderOutline(outlines, folder).Outlines
+			}
+		}
+		*outlines = append(*outlines, Outline{
+			Text:    subscription.Title,
+			Title:   subscription.Title,
+			Type:    "rss",
+			XMLURL:  subscription.XMLURL,
+			HTMLURL: subscription.HTMLURL,
+		})
+	}
+	return doc
+}
+
+func folderOutline(outlines *[]Outline, name string) *Outline {
+	for i := range *outlines {
+		outline := &(*outlines)[i]
+		if outline.XMLURL == "" && outline.Text == name {
+			return outline
+		}
+	}
+	*outlines = append(*outlines, Outline{Text: name, Title: name})
+	return &(*outlines)[len(*outlines)-1]
+}
+
+func (doc *OPML) Write(w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil { return fmt.Errorf("error writing OPML: %v", err) }
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(doc)
+	if err != nil { return fmt.Errorf("error writing OPML: %v", err) }
+	_, err = io.WriteString(w, "\n")
+	if err != nil { return fmt.Errorf("error writing OPML: %v", err) }
+	return nil
+}
@@ -0,0 +1,107 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type atomFeed struct {
+	Title    atomText    `xml:"title"`
+	Subtitle atomText    `xml:"subtitle"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     atomText   `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Summary   atomText   `xml:"summary"`
+	Content   atomText   `xml:"content"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// atomText is an Atom text construct. XHTML content is kept as markup,
+// text and html content as character data.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+func parseAtom(data []byte) (*RSSFeed, error) {
+	atom := atomFeed{}
+	err := xml.Unmarshal(data, &atom)
+	if err != nil { return nil, fmt.Errorf("error parsing Atom: %v", err) }
+
+	feed := RSSFeed{}
+	feed.Channel.Title = atom.Title.String()
+	feed.Channel.Link = alternateLink(atom.Links)
+	feed.Channel.Description = atom.Subtitle.String()
+	for _, entry := range atom.Entries {
+		description := entry.Summary.String()
+		if description == "" {
+			description = entry.Content.String()
+		}
+		date := entry.Published
+		if date == "" {
+			date = entry.Updated
+		}
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			Title:       entry.Title.String(),
+			Link:        alternateLink(entry.Links),
+			Description: description,
+			Content:     entry.Content.String(),
+			PubDate:     w3cDate(date),
+			GUID:        strings.TrimSpace(entry.ID),
+			Enclosures:  enclosureLinks(entry.Links),
+		})
+	}
+	return &feed, nil
+}
+
+// enclosureLinks returns the rel="enclosure" links, Atom's equivalent of
+// RSS <enclosure>.
+func enclosureLinks(links []atomLink) []RSSEnclosure {
+	var enclosures []RSSEnclosure
+	for _, link := range links {
+		if link.Rel == "enclosure" {
+			enclosures = append(enclosures, RSSEnclosure{
+				URL:       link.Href,
+				Type:      link.Type,
+				RawLength: link.Length,
+			})
+		}
+	}
+	return enclosures
+}
+
+// alternateLink picks the rel="alternate" link, which is also the default
+// when rel is omitted, falling back to the first link present.
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
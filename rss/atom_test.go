@@ -0,0 +1,144 @@
+package rss
+
+import "testing"
+
+func TestParseAtomFeed(t *testing.T) {
+	feed, err := ParseFeed([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example</title>
+	<subtitle>News from example.com</subtitle>
+	<link rel="self" href="https://example.com/feed.atom"/>
+	<link href="https://example.com/"/>
+	<id>urn:uuid:60a76c80-d399-11d9-b93c-0003939e0af6</id>
+	<updated>2026-01-03T10:00:00Z</updated>
+</feed>`))
+	if err != nil { t.Fatalf("ParseFeed: %v", err) }
+	if feed.Channel.Title != "Example" || feed.Channel.Description != "News from example.com" {
+		t.Errorf("channel = %q / %q, want the title and subtitle", feed.Channel.Title, feed.Channel.Description)
+	}
+	if feed.Channel.Link != "https://example.com/" {
+		t.Errorf("channel link = %q, want the alternate link, not self", feed.Channel.Link)
+	}
+}
+
+func TestParseAtomEntries(t *testing.T) {
+	tests := []struct {
+		name string
+		entry string
+		want RSSItem
+	}{
+		{
+			name: "alternate link and enclosure",
+			entry: `<entry>
+				<title>Episode 1</title>
+				<id>tag:example.com,2026:1</id>
+				<link rel="enclosure" href="https://example.com/1.mp3" type="audio/mpeg" length="1234"/>
+				<link rel="alternate" type="text/html" href="https://example.com/1"/>
+				<updated>2026-01-02T10:00:00Z</updated>
+			</entry>`,
+			want: RSSItem{
+				Title: "Episode 1",
+				Link: "https://example.com/1",
+				GUID: "tag:example.com,2026:1",
+				PubDate: "Fri, 02 Jan 2026 10:00:00 +0000",
+				Enclosures: []RSSEnclosure{{URL: "https://example.com/1.mp3", Type: "audio/mpeg", RawLength: "1234"}},
+			},
+		},
+		{
+			name: "link without rel is the alternate",
+			entry: `<entry>
+				<title>Post</title>
+				<link rel="related" href="https://other.example/"/>
+				<link href="https://example.com/post"/>
+				<id> urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a </id>
+			</entry>`,
+			want: RSSItem{
+				Title: "Post",
+				Link: "https://example.com/post",
+				GUID: "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
+			},
+		},
+		{
+			name: "published wins over updated",
+			entry: `<entry>
+				<title>Edited</title>
+				<published>2026-01-01T08:30:00+01:00</published>
+				<updated>2026-01-05T12:00:00Z</updated>
+			</entry>`,
+			want: RSSItem{Title: "Edited", PubDate: "Thu, 01 Jan 2026 08:30:00 +0100"},
+		},
+		{
+			name: "html content with a summary",
+			entry: `<entry>
+				<title type="html">Fish &amp;amp; chips</title>
+				<summary>Short version</summary>
+				<content type="html">&lt;p&gt;Long &lt;b&gt;version&lt;/b&gt;&lt;/p&gt;</content>
+			</entry>`,
+			want: RSSItem{
+				Title: "Fish &amp; chips",
+				Description: "Short version",
+				Content: "<p>Long <b>version</b></p>",
+			},
+		},
+		{
+			name: "content stands in for a missing summary",
+			entry: `<entry>
+				<title>Only content</title>
+				<content type="html">&lt;p&gt;Everything&lt;/p&gt;</content>
+			</entry>`,
+			want: RSSItem{
+				Title: "Only content",
+				Description: "<p>Everything</p>",
+				Content: "<p>Everything</p>",
+			},
+		},
+		{
+			name: "xhtml content keeps its markup",
+			entry: `<entry>
+				<title>XHTML</title>
+				<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Hi</p></div></content>
+			</entry>`,
+			want: RSSItem{
+				Title: "XHTML",
+				Description: `<div xmlns="http://www.w3.org/1999/xhtml"><p>Hi</p></div>`,
+				Content: `<div xmlns="http://www.w3.org/1999/xhtml"><p>Hi</p></div>`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed, err := ParseFeed([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><title>Example</title>` + test.entry + `</feed>`))
+			if err != nil { t.Fatalf("ParseFeed: %v", err) }
+			if len(feed.Channel.Item) != 1 {
+				t.Fatalf("got %d items, want 1", len(feed.Channel.Item))
+			}
+			assertItem(t, feed.Channel.Item[0], test.want)
+		})
+	}
+}
+
+// assertItem compares the fields every feed format fills in.
+func assertItem(t *testing.T, got, want RSSItem) {
+	t.Helper()
+	for _, field := range []struct{ name, got, want string }{
+		{"title", got.Title, want.Title},
+		{"link", got.Link, want.Link},
+		{"guid", got.GUID, want.GUID},
+		{"pubDate", got.PubDate, want.PubDate},
+		{"description", got.Description, want.Description},
+		{"content", got.Content, want.Content},
+		{"author", got.Author, want.Author},
+	} {
+		if field.got != field.want {
+			t.Errorf("%s = %q, want %q", field.name, field.got, field.want)
+		}
+	}
+	if len(got.Enclosures) != len(want.Enclosures) {
+		t.Fatalf("enclosures = %+v, want %+v", got.Enclosures, want.Enclosures)
+	}
+	for i := range want.Enclosures {
+		if got.Enclosures[i] != want.Enclosures[i] {
+			t.Errorf("enclosure %d = %+v, want %+v", i, got.Enclosures[i], want.Enclosures[i])
+		}
+	}
+}
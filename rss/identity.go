@@ -0,0 +1,63 @@
+package rss
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that only tell the publisher where
+// a click came from, so links differing in them point at the same item.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"ref":     true,
+	"ref_src": true,
+}
+
+// Identity identifies the item within its feed: its guid, or its
+// normalized link when the feed gives none. Items with neither fall back
+// to the title.
+func (item *RSSItem) Identity() string {
+	if guid := strings.TrimSpace(item.GUID); guid != "" {
+		return guid
+	}
+	if link := strings.TrimSpace(item.Link); link != "" {
+		return NormalizeLink(link)
+	}
+	return strings.TrimSpace(item.Title)
+}
+
+
+// NormalizeLink reduces a link to a canonical form: lowercase scheme and
+// host, no default port, fragment or tracking parameters, and the
+// remaining query parameters sorted. Links that do not parse as absolute
+// URLs are returned trimmed.
+func NormalizeLink(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return link
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for name := range query {
+		if strings.HasPrefix(name, "utm_") || trackingParams[name] {
+			query.Del(name)
+		}
+	}
+	// Encode sorts by key
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,132 @@
+package rss
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jsonFeed is a JSON Feed 1.1 document. Version 1.0 fields that were
+// replaced in 1.1 (author) are still read.
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            jsonFeedID           `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors"`
+	Author        *jsonFeedAuthor      `json:"author"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+}
+
+// jsonFeedID is an item id. The spec makes it a string, but some
+// publishers write numbers, which are kept as their decimal text.
+type jsonFeedID string
+
+func (id *jsonFeedID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, (*string)(id))
+	}
+	var number json.Number
+	err := json.Unmarshal(data, &number)
+	if err != nil { return fmt.Errorf("item id is neither a string nor a number: %s", data) }
+	*id = jsonFeedID(number)
+	return nil
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonFeedAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+func parseJSONFeed(data []byte) (*RSSFeed, error) {
+	doc := jsonFeed{}
+	err := json.Unmarshal(data, &doc)
+	if err != nil { return nil, fmt.Errorf("error parsing JSON Feed: %v", err) }
+	if !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("error parsing JSON Feed: unknown version %q", doc.Version)
+	}
+
+	feed := RSSFeed{}
+	feed.Channel.Title = doc.Title
+	feed.Channel.Link = doc.HomePageURL
+	feed.Channel.Description = doc.Description
+	for _, item := range doc.Items {
+		feed.Channel.Item = append(feed.Channel.Item, item.toRSSItem())
+	}
+	return &feed, nil
+}
+
+func (item jsonFeedItem) toRSSItem() RSSItem {
+	link := item.URL
+	if link == "" {
+		link = item.ExternalURL
+	}
+	content := item.ContentHTML
+	if content == "" {
+		content = item.ContentText
+	}
+	description := item.Summary
+	if description == "" {
+		description = content
+	}
+	date := item.DatePublished
+	if date == "" {
+		date = item.DateModified
+	}
+
+	authors := item.Authors
+	if len(authors) == 0 && item.Author != nil {
+		authors = []jsonFeedAuthor{*item.Author}
+	}
+	names := make([]string, 0, len(authors))
+	for _, author := range authors {
+		if author.Name != "" {
+			names = append(names, author.Name)
+		}
+	}
+
+	enclosures := make([]RSSEnclosure, 0, len(item.Attachments))
+	for _, attachment := range item.Attachments {
+		enclosures = append(enclosures, RSSEnclosure{
+			URL:      attachment.URL,
+			Type:     attachment.MimeType,
+			Length:   attachment.SizeInBytes,
+			Duration: time.Duration(attachment.DurationInSeconds * float64(time.Second)),
+		})
+	}
+
+	return RSSItem{
+		Title:       item.Title,
+		Link:        link,
+		Description: description,
+		Content:     content,
+		PubDate:     w3cDate(date),
+		GUID:        string(item.ID),
+		Author:      strings.Join(names, ", "),
+		Enclosures:  enclosures,
+	}
+}
@@ -0,0 +1,37 @@
+package rss
+
+import "testing"
+
+func TestJSONFeedItemIDs(t *testing.T) {
+	feed, err := ParseFeed([]byte(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Feed",
+		"items": [
+			{"id": "abc", "url": "https://example.com/a"},
+			{"id": 12345, "url": "https://example.com/b"},
+			{"id": 1.5e3, "url": "https://example.com/c"},
+			{"id": null, "url": "https://example.com/d"}
+		]
+	}`))
+	if err != nil { t.Fatalf("ParseFeed: %v", err) }
+
+	want := []string{"abc", "12345", "1.5e3", ""}
+	if len(feed.Channel.Item) != len(want) {
+		t.Fatalf("got %d items, want %d", len(feed.Channel.Item), len(want))
+	}
+	for i, item := range feed.Channel.Item {
+		if item.GUID != want[i] {
+			t.Errorf("item %d GUID = %q, want %q", i, item.GUID, want[i])
+		}
+	}
+}
+
+func TestJSONFeedRejectsObjectID(t *testing.T) {
+	_, err := ParseFeed([]byte(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"items": [{"id": {"x": 1}}]
+	}`))
+	if err == nil {
+		t.Error("ParseFeed accepted an object as an item id")
+	}
+}
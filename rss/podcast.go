@@ -0,0 +1,140 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The podcast fields of RSSItem come from the iTunes namespace and Media
+// RSS, read alongside the plain <enclosure> elements.
+
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// MediaContent is a Media RSS <media:content>. Duration is in seconds.
+type MediaContent struct {
+	URL string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Duration string `xml:"duration,attr"`
+}
+
+type MediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type MediaGroup struct {
+	Content []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnail []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+
+// Media returns the item's enclosures followed by its Media RSS content
+// that is not already enclosed. Lengths that are not a number of bytes
+// are dropped. Enclosures without a duration of their own take the item's
+// itunes:duration.
+func (item *RSSItem) Media() []RSSEnclosure {
+	var media []RSSEnclosure
+	seen := make(map[string]bool)
+	add := func(enclosure RSSEnclosure) {
+		enclosure.URL = strings.TrimSpace(enclosure.URL)
+		if enclosure.URL == "" || seen[enclosure.URL] {
+			return
+		}
+		seen[enclosure.URL] = true
+		if enclosure.Length == 0 {
+			enclosure.Length = parseLength(enclosure.RawLength)
+		}
+		media = append(media, enclosure)
+	}
+
+	for _, enclosure := range item.Enclosures {
+		add(enclosure)
+	}
+	contents := item.MediaContent
+	for _, group := range item.MediaGroups {
+		contents = append(contents, group.Content...)
+	}
+	for _, content := range contents {
+		// images are thumbnails rather than episodes
+		if content.Medium == "image" || strings.HasPrefix(content.Type, "image/") {
+			continue
+		}
+		add(RSSEnclosure{
+			URL: content.URL,
+			Type: content.Type,
+			RawLength: content.FileSize,
+			Duration: parseDuration(content.Duration),
+		})
+	}
+
+	duration := parseDuration(item.ITunesDuration)
+	for i := range media {
+		if media[i].Duration == 0 {
+			media[i].Duration = duration
+		}
+	}
+	return media
+}
+
+
+// Episode is the itunes:episode number, zero when absent or malformed.
+func (item *RSSItem) Episode() int {
+	episode, err := strconv.Atoi(strings.TrimSpace(item.ITunesEpisode))
+	if err != nil || episode < 0 {
+		return 0
+	}
+	return episode
+}
+
+
+// Image is the episode artwork: itunes:image, or else the first Media RSS
+// thumbnail.
+func (item *RSSItem) Image() string {
+	if href := strings.TrimSpace(item.ITunesImage.Href); href != "" {
+		return href
+	}
+	thumbnails := item.MediaThumbnails
+	for _, group := range item.MediaGroups {
+		thumbnails = append(thumbnails, group.Thumbnail...)
+	}
+	for _, thumbnail := range thumbnails {
+		if url := strings.TrimSpace(thumbnail.URL); url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+
+// parseLength reads a size in bytes, zero when it is not a positive
+// integer.
+func parseLength(value string) int64 {
+	length, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || length < 0 {
+		return 0
+	}
+	return length
+}
+
+
+// parseDuration reads itunes:duration, which is either a number of seconds
+// or HH:MM:SS / MM:SS. Malformed values are zero.
+func parseDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	var seconds float64
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,88 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+const podcastFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+	<title>Podcast</title>
+	<item>
+		<title>Episode 1</title>
+		<guid>ep1</guid>
+		<enclosure url="https://example.com/ep1.mp3" type="audio/mpeg" length="unknown"/>
+		<itunes:duration>1:02:03</itunes:duration>
+		<itunes:episode>1</itunes:episode>
+	</item>
+	<item>
+		<title>Episode 2</title>
+		<guid>ep2</guid>
+		<enclosure url="https://example.com/ep2.mp3" type="audio/mpeg" length="12345"/>
+		<media:content url="https://example.com/ep2.mp4" type="video/mp4" fileSize="n/a" duration="95"/>
+		<media:content url="https://example.com/cover.jpg" medium="image"/>
+	</item>
+</channel>
+</rss>`
+
+func TestMediaToleratesNonNumericLengths(t *testing.T) {
+	feed, err := ParseFeed([]byte(podcastFeed))
+	if err != nil { t.Fatalf("ParseFeed: %v", err) }
+	if len(feed.Channel.Item) != 2 {
+		t.Fatalf("got %d items, want 2", len(feed.Channel.Item))
+	}
+
+	first := feed.Channel.Item[0].Media()
+	if len(first) != 1 {
+		t.Fatalf("got %d enclosures, want 1", len(first))
+	}
+	if first[0].Length != 0 {
+		t.Errorf("length %q parsed as %d, want 0", first[0].RawLength, first[0].Length)
+	}
+	if want := time.Hour + 2*time.Minute + 3*time.Second; first[0].Duration != want {
+		t.Errorf("duration = %v, want %v", first[0].Duration, want)
+	}
+
+	second := feed.Channel.Item[1].Media()
+	if len(second) != 2 {
+		t.Fatalf("got %d enclosures, want 2 without the image", len(second))
+	}
+	if second[0].Length != 12345 {
+		t.Errorf("length = %d, want 12345", second[0].Length)
+	}
+	if second[1].Length != 0 || second[1].Duration != 95*time.Second {
+		t.Errorf("media content = %+v, want no length and 95s", second[1])
+	}
+}
+
+func TestAtomEnclosureLinks(t *testing.T) {
+	feed, err := ParseFeed([]byte(`<feed xmlns="http://www.w3.org/2005/Atom">
+	<entry>
+		<id>urn:1</id>
+		<link href="https://example.com/1"/>
+		<link rel="enclosure" href="https://example.com/1.ogg" type="audio/ogg" length=""/>
+	</entry>
+</feed>`))
+	if err != nil { t.Fatalf("ParseFeed: %v", err) }
+	media := feed.Channel.Item[0].Media()
+	if len(media) != 1 || media[0].URL != "https://example.com/1.ogg" || media[0].Type != "audio/ogg" {
+		t.Errorf("Media() = %+v, want the enclosure link", media)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"": 0,
+		"90": 90 * time.Second,
+		"1:30": 90 * time.Second,
+		"01:00:05": time.Hour + 5*time.Second,
+		"abc": 0,
+		"-5": 0,
+	}
+	for value, want := range tests {
+		if got := parseDuration(value); got != want {
+			t.Errorf("parseDuration(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Output is a feed gator publishes, such as a user's combined timeline.
+type Output struct {
+	// ID identifies the feed permanently, as a URI.
+	ID          string
+	Title       string
+	Description string
+	Author      string
+	// Link is the feed's own URL, if it is served anywhere.
+	Link    string
+	Updated time.Time
+	Items   []OutputItem
+}
+
+type OutputItem struct {
+	// ID identifies the item permanently, as a URI. It is used as the
+	// RSS guid and Atom id.
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time
+	// SourceTitle and SourceURL attribute the item to the feed it was
+	// aggregated from.
+	SourceTitle string
+	SourceURL   string
+}
+
+type rssOutput struct {
+	XMLName xml.Name         `xml:"rss"`
+	Version string           `xml:"version,attr"`
+	AtomNS  string           `xml:"xmlns:atom,attr"`
+	Channel rssOutputChannel `xml:"channel"`
+}
+
+type rssOutputChannel struct {
+	Title         string          `xml:"title"`
+	Link          string          `xml:"link"`
+	Description   string          `xml:"description"`
+	AtomLink      *atomOutputLink `xml:"atom:link,omitempty"`
+	LastBuildDate string          `xml:"lastBuildDate"`
+	Generator     string          `xml:"generator"`
+	Items         []rssOutputItem `xml:"item"`
+}
+
+type rssOutputItem struct {
+	Title       string          `xml:"title"`
+	Link        string          `xml:"link,omitempty"`
+	Description string          `xml:"description"`
+	PubDate     string          `xml:"pubDate"`
+	GUID        rssOutputGUID   `xml:"guid"`
+	Source      rssOutputSource `xml:"source"`
+}
+
+type rssOutputGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssOutputSource struct {
+	URL   string `xml:"url,attr"`
+	Title string `xml:",chardata"`
+}
+
+// WriteRSS renders out as an RSS 2.0 document. RSS 2.0 requires a
+// channel link, so out.Link must be set.
+func WriteRSS(w io.Writer, out *Output) error {
+	if out.Link == "" {
+		return fmt.Errorf("error writing RSS: the feed has no link")
+	}
+	doc := rssOutput{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssOutputChannel{
+			Title:         out.Title,
+			Link:          out.Link,
+			Description:   out.Description,
+			AtomLink:      &atomOutputLink{Href: out.Link, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: out.Updated.Format(time.RFC1123Z),
+			Generator:     "gator",
+		},
+	}
+	for _, item := range out.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssOutputItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			PubDate:     item.Published.Format(time.RFC1123Z),
+			GUID:        rssOutputGUID{IsPermaLink: "false", Value: item.ID},
+			Source:      rssOutputSource{URL: item.SourceURL, Title: item.SourceTitle},
+		})
+	}
+	return writeXML(w, doc)
+}
+
+type atomOutput struct {
+	XMLName xml.Name         `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string           `xml:"id"`
+	Title   string           `xml:"title"`
+	Updated string           `xml:"updated"`
+	Author  atomOutputAuthor `xml:"author"`
+	Links   []atomOutputLink `xml:"link"`
+	Entries []atomOutputItem `xml:"entry"`
+}
+
+type atomOutputAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomOutputLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomOutputItem struct {
+	ID        string           `xml:"id"`
+	Title     string           `xml:"title"`
+	Links     []atomOutputLink `xml:"link"`
+	Published string           `xml:"published"`
+	Updated   string           `xml:"updated"`
+	Summary   atomOutputText   `xml:"summary"`
+	Source    atomOutputSource `xml:"source"`
+}
+
+type atomOutputText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomOutputSource struct {
+	ID    string           `xml:"id"`
+	Title string           `xml:"title"`
+	Links []atomOutputLink `xml:"link"`
+}
+
+// WriteAtom renders out as an Atom 1.0 document.
+func WriteAtom(w io.Writer, out *Output) error {
+	doc := atomOutput{
+		ID:      out.ID,
+		Title:   out.Title,
+		Updated: out.Updated.Format(time.RFC3339),
+		Author:  atomOutputAuthor{Name: out.Author},
+	}
+	if out.Link != "" {
+		doc.Links = append(doc.Links, atomOutputLink{Href: out.Link, Rel: "self", Type: "application/atom+xml"})
+	}
+	for _, item := range out.Items {
+		entry := atomOutputItem{
+			ID:        item.ID,
+			Title:     item.Title,
+			Published: item.Published.Format(time.RFC3339),
+			Updated:   item.Published.Format(time.RFC3339),
+			Summary:   atomOutputText{Type: "html", Value: item.Description},
+			Source: atomOutputSource{
+				ID:    item.SourceURL,
+				Title: item.SourceTitle,
+				Links: []atomOutputLink{{Href: item.SourceURL, Rel: "self"}},
+			},
+		}
+		if item.Link != "" {
+			entry.Links = append(entry.Links, atomOutputLink{Href: item.Link, Rel: "alternate"})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc any) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil { return fmt.Errorf("error writing feed: %v", err) }
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(doc)
+	if err != nil { return fmt.Errorf("error writing feed: %v", err) }
+	_, err = io.WriteString(w, "\n")
+	if err != nil { return fmt.Errorf("error writing feed: %v", err) }
+	return nil
+}
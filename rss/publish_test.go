@@ -0,0 +1,69 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testOutput(link string) *Output {
+	return &Output{
+		ID:          "urn:uuid:1",
+		Title:       "Timeline",
+		Description: "Posts",
+		Author:      "alice",
+		Link:        link,
+		Updated:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Items: []OutputItem{{
+			ID:          "urn:uuid:2",
+			Title:       "Post",
+			Link:        "https://example.com/post",
+			Description: "Body",
+			Published:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			SourceTitle: "Example",
+			SourceURL:   "https://example.com/feed.xml",
+		}},
+	}
+}
+
+func TestWriteRSSRequiresLink(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteRSS(&buf, testOutput(""))
+	if err == nil {
+		t.Fatal("WriteRSS without a link succeeded")
+	}
+}
+
+func TestWriteRSSChannelLink(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteRSS(&buf, testOutput("https://example.com/timeline.xml"))
+	if err != nil { t.Fatalf("WriteRSS: %v", err) }
+
+	// the channel link, not the atom:link beside it
+	if !strings.Contains(buf.String(), "<link>https://example.com/timeline.xml</link>") {
+		t.Errorf("channel link missing from %s", buf.String())
+	}
+	var doc struct {
+		Channel struct {
+			Items []struct {
+				GUID string `xml:"guid"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	err = xml.Unmarshal(buf.Bytes(), &doc)
+	if err != nil { t.Fatalf("output is not XML: %v", err) }
+	if len(doc.Channel.Items) != 1 || doc.Channel.Items[0].GUID != "urn:uuid:2" {
+		t.Errorf("items = %+v", doc.Channel.Items)
+	}
+}
+
+func TestWriteAtomWithoutLink(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteAtom(&buf, testOutput(""))
+	if err != nil { t.Fatalf("WriteAtom: %v", err) }
+	if !strings.Contains(buf.String(), "<id>urn:uuid:1</id>") {
+		t.Errorf("feed id missing from %s", buf.String())
+	}
+}
@@ -0,0 +1,55 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// rdfFeed is an RSS 1.0 document. Unlike RSS 2.0 the items are siblings
+// of the channel rather than children of it.
+type rdfFeed struct {
+	Channel struct {
+		Title           string `xml:"title"`
+		Link            string `xml:"link"`
+		Description     string `xml:"description"`
+		UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+	} `xml:"channel"`
+	Items []rdfItem `xml:"item"`
+}
+
+type rdfItem struct {
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+}
+
+func parseRDF(data []byte) (*RSSFeed, error) {
+	rdf := rdfFeed{}
+	err := xml.Unmarshal(data, &rdf)
+	if err != nil { return nil, fmt.Errorf("error parsing RDF: %v", err) }
+
+	feed := RSSFeed{}
+	feed.Channel.Title = strings.TrimSpace(rdf.Channel.Title)
+	feed.Channel.Link = strings.TrimSpace(rdf.Channel.Link)
+	feed.Channel.Description = strings.TrimSpace(rdf.Channel.Description)
+	feed.Channel.UpdatePeriod = rdf.Channel.UpdatePeriod
+	feed.Channel.UpdateFrequency = rdf.Channel.UpdateFrequency
+	for _, item := range rdf.Items {
+		feed.Channel.Item = append(feed.Channel.Item, RSSItem{
+			Title:       strings.TrimSpace(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Description: strings.TrimSpace(item.Description),
+			Content:     strings.TrimSpace(item.Content),
+			PubDate:     w3cDate(item.Date),
+			GUID:        item.About,
+			Author:      strings.TrimSpace(item.Creator),
+		})
+	}
+	return &feed, nil
+}
@@ -0,0 +1,66 @@
+package rss
+
+import "testing"
+
+func TestParseRDF(t *testing.T) {
+	feed, err := ParseFeed([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<rdf:RDF
+	xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns="http://purl.org/rss/1.0/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/">
+	<channel rdf:about="https://example.com/">
+		<title>Example</title>
+		<link>https://example.com/</link>
+		<description>News from example.com</description>
+		<sy:updatePeriod>daily</sy:updatePeriod>
+		<sy:updateFrequency>2</sy:updateFrequency>
+		<items>
+			<rdf:Seq>
+				<rdf:li rdf:resource="https://example.com/1"/>
+				<rdf:li rdf:resource="https://example.com/2"/>
+			</rdf:Seq>
+		</items>
+	</channel>
+	<item rdf:about="https://example.com/1">
+		<title>First</title>
+		<link>https://example.com/1</link>
+		<description>Summary of the first</description>
+		<content:encoded><![CDATA[<p>All of the first</p>]]></content:encoded>
+		<dc:date>2026-01-02T10:00:00+09:00</dc:date>
+		<dc:creator>Alice</dc:creator>
+	</item>
+	<item rdf:about="https://example.com/2">
+		<title>Second</title>
+		<link>https://example.com/2</link>
+		<dc:date>2026-01-03</dc:date>
+	</item>
+</rdf:RDF>`))
+	if err != nil { t.Fatalf("ParseFeed: %v", err) }
+
+	if feed.Channel.Title != "Example" || feed.Channel.Link != "https://example.com/" {
+		t.Errorf("channel = %q at %q, want Example at https://example.com/", feed.Channel.Title, feed.Channel.Link)
+	}
+	if schedule := feed.Schedule(); schedule.Interval.Hours() != 12 {
+		t.Errorf("interval = %v, want twice daily", schedule.Interval)
+	}
+	if len(feed.Channel.Item) != 2 {
+		t.Fatalf("got %d items, want the two siblings of the channel", len(feed.Channel.Item))
+	}
+	assertItem(t, feed.Channel.Item[0], RSSItem{
+		Title: "First",
+		Link: "https://example.com/1",
+		GUID: "https://example.com/1",
+		PubDate: "Fri, 02 Jan 2026 10:00:00 +0900",
+		Description: "Summary of the first",
+		Content: "<p>All of the first</p>",
+		Author: "Alice",
+	})
+	assertItem(t, feed.Channel.Item[1], RSSItem{
+		Title: "Second",
+		Link: "https://example.com/2",
+		GUID: "https://example.com/2",
+		PubDate: "Sat, 03 Jan 2026 00:00:00 +0000",
+	})
+}
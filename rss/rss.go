@@ -2,56 +2,202 @@
 package rss
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"context"
 	"encoding/xml"
+	"errors"
 	"io"
-	//"strings"
+	"mime"
+	"strings"
+	"time"
 )
 
 type RSSFeed struct {
 	Channel struct {
 		Title string `xml:"title"`
-		Link string `xml:"link"`
+		Link string `xml:"-"`
+		// Links also collects the atom:link self links many feeds carry,
+		// which Link must not be taken from.
+		Links []namespacedText `xml:"link"`
 		Description string `xml:"description"`
+		TTL string `xml:"ttl"`
+		UpdatePeriod string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+		SkipHours []string `xml:"skipHours>hour"`
+		SkipDays []string `xml:"skipDays>day"`
 		Item []RSSItem `xml:"item"`
 	} `xml:"channel"`
 } 
 
 type RSSItem struct {
 	Title string `xml:"title"`
-	Link string `xml:"link"`
+	Link string `xml:"-"`
+	Links []namespacedText `xml:"link"`
 	Description string `xml:"description"`
+	// Content is the full article from content:encoded, where
+	// Description is often a summary.
+	Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 	PubDate string `xml:"pubDate"`
+	GUID string `xml:"guid"`
+	Author string `xml:"author"`
+	Enclosures []RSSEnclosure `xml:"enclosure"`
+	ITunesDuration string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesEpisode string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesImage ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	MediaContent []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroups []MediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
+	MediaThumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+// namespacedText is an element's text along with its name, for elements
+// such as link that other namespaces reuse.
+type namespacedText struct {
+	XMLName xml.Name
+	Text string `xml:",chardata"`
+}
+
+// plainText returns the text of the first element outside any namespace.
+func plainText(elements []namespacedText) string {
+	for _, element := range elements {
+		if element.XMLName.Space == "" {
+			return element.Text
+		}
+	}
+	return ""
+}
+
+
+type RSSEnclosure struct {
+	URL string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+	// RawLength is the length attribute as given, which is not always a
+	// number ("unknown", "0", or empty).
+	RawLength string `xml:"length,attr"`
+	// Length and Duration are filled in by RSSItem.Media.
+	Length int64 `xml:"-"`
+	Duration time.Duration `xml:"-"`
 }
 
 
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+// CacheValidators are the HTTP validators returned with a feed, sent back
+// on the next request so an unchanged feed can be answered with a 304.
+type CacheValidators struct {
+	ETag string
+	LastModified string
+}
+
+// ErrNotModified is returned by FetchFeed when the server reports that the
+// feed has not changed since the validators passed in were issued.
+var ErrNotModified = errors.New("feed not modified")
+
+
+func FetchFeed(ctx context.Context, feedURL string, cache CacheValidators) (*RSSFeed, CacheValidators, error) {
 	req	, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-	if err != nil { return nil, fmt.Errorf("error fetching feed: %v", err) }
+	if err != nil { return nil, cache, fmt.Errorf("error fetching feed: %v", err) }
 
 	req.Header.Set("User-Agent", "gator")
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
 	client := &http.Client{}
 	resp, err := client.Do(req)
-	if err != nil { return nil, fmt.Errorf("error fetching feed: %v", err) }
+	if err != nil { return nil, cache, fmt.Errorf("error fetching feed: %v", err) }
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, cache, ErrNotModified
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("error fetching feed: %v", err)
+		return nil, cache, fmt.Errorf("error fetching feed: unexpected status %s", resp.Status)
 	}
 
-	feed := RSSFeed{}
 	feedContent, err := io.ReadAll(resp.Body)
-	if err != nil { return nil, fmt.Errorf("error fetching feed: %v", err) }
+	if err != nil { return nil, cache, fmt.Errorf("error fetching feed: %v", err) }
+
+	validators := CacheValidators{
+		ETag: resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	var feed *RSSFeed
+	if isJSONContentType(resp.Header.Get("Content-Type")) {
+		feed, err = parseJSONFeed(feedContent)
+	} else {
+		feed, err = ParseFeed(feedContent)
+	}
+	if err != nil { return nil, cache, err }
+	return feed, validators, nil
+}
+
+
+// ParseFeed decodes an RSS 2.0, RSS 1.0 (RDF), Atom 1.0 or JSON Feed
+// document into an RSSFeed. JSON is recognised by the body, XML formats by the root element.
+func ParseFeed(data []byte) (*RSSFeed, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseJSONFeed(data)
+	}
+
+	root, err := rootElement(data)
+	if err != nil { return nil, fmt.Errorf("error parsing XML: %v", err) }
+
+	switch root.Local {
+	case "feed":
+		return parseAtom(data)
+	case "RDF":
+		return parseRDF(data)
+	default:
+		feed := RSSFeed{}
+		err = xml.Unmarshal(data, &feed)
+		if err != nil { return nil, fmt.Errorf("error parsing XML: %v", err) }
+		feed.Channel.Link = plainText(feed.Channel.Links)
+		for i := range feed.Channel.Item {
+			feed.Channel.Item[i].Link = plainText(feed.Channel.Item[i].Links)
+		}
+		return &feed, nil
+	}
+}
 
-	err = xml.Unmarshal(feedContent, &feed)
-	if err != nil {
-    	return nil, fmt.Errorf("error parsing XML: %v", err)
+
+func rootElement(data []byte) (xml.Name, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil { return xml.Name{}, err }
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name, nil
+		}
 	}
+}
 
-	return &feed, nil
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil { return false }
+	return mediaType == "application/feed+json" || mediaType == "application/json"
 }
 
 
+// w3cDate converts a W3C-DTF / RFC 3339 timestamp, as used by Atom, JSON
+// Feed and Dublin Core, to the RFC 1123 form of RSS pubDate so all items
+// parse alike.
+func w3cDate(date string) string {
+	layouts := []string{
+		time.RFC3339,
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02",
+	}
+	date = strings.TrimSpace(date)
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, date)
+		if err == nil {
+			return t.Format(time.RFC1123Z)
+		}
+	}
+	return date
+}
@@ -0,0 +1,26 @@
+package rss
+
+import "testing"
+
+func TestParseFeedIgnoresAtomLinks(t *testing.T) {
+	feed, err := ParseFeed([]byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+	<title>Example</title>
+	<link>https://example.com/</link>
+	<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+	<item>
+		<title>Post</title>
+		<atom:link href="https://example.com/post/amp" rel="amphtml"/>
+		<link>https://example.com/post</link>
+	</item>
+</channel>
+</rss>`))
+	if err != nil { t.Fatalf("ParseFeed: %v", err) }
+	if feed.Channel.Link != "https://example.com/" {
+		t.Errorf("channel link = %q, want the site", feed.Channel.Link)
+	}
+	if len(feed.Channel.Item) != 1 || feed.Channel.Item[0].Link != "https://example.com/post" {
+		t.Errorf("items = %+v, want the post link", feed.Channel.Item)
+	}
+}
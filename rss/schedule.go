@@ -0,0 +1,78 @@
+package rss
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is the polling guidance a feed publishes about itself through
+// RSS <ttl>, <skipHours> and <skipDays> and the syndication module.
+type Schedule struct {
+	// Interval is the shortest time the publisher asks clients to wait
+	// between fetches, zero when the feed gives no hint.
+	Interval time.Duration
+	// SkipHours are the hours (0-23, GMT) in which the feed should not
+	// be fetched.
+	SkipHours []int
+	// SkipDays are the weekdays on which the feed should not be fetched.
+	SkipDays []time.Weekday
+}
+
+// maxTTL is the longest <ttl>, in minutes, that fits a time.Duration.
+const maxTTL = int(math.MaxInt64 / time.Minute)
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Schedule reads the feed's polling hints. When both <ttl> and the
+// syndication module are present the longer interval wins. Malformed
+// values are ignored.
+func (feed *RSSFeed) Schedule() Schedule {
+	schedule := Schedule{}
+	channel := feed.Channel
+
+	ttl, err := strconv.Atoi(strings.TrimSpace(channel.TTL))
+	if err == nil && ttl > 0 {
+		schedule.Interval = time.Duration(min(ttl, maxTTL)) * time.Minute
+	}
+
+	period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(channel.UpdatePeriod))]
+	if ok {
+		frequency, err := strconv.Atoi(strings.TrimSpace(channel.UpdateFrequency))
+		if err != nil || frequency < 1 {
+			frequency = 1
+		}
+		schedule.Interval = max(schedule.Interval, period/time.Duration(frequency))
+	}
+
+	for _, value := range channel.SkipHours {
+		hour, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil && hour >= 0 && hour < 24 {
+			schedule.SkipHours = append(schedule.SkipHours, hour)
+		}
+	}
+	for _, value := range channel.SkipDays {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(value))]
+		if ok {
+			schedule.SkipDays = append(schedule.SkipDays, day)
+		}
+	}
+	return schedule
+}
@@ -0,0 +1,23 @@
+package rss
+
+import "testing"
+
+func TestScheduleTTL(t *testing.T) {
+	for _, test := range []struct {
+		ttl string
+		positive bool
+	}{
+		{"60", true},
+		{"9223372036854775807", true},
+		{"0", false},
+		{"-5", false},
+		{"soon", false},
+	} {
+		feed := &RSSFeed{}
+		feed.Channel.TTL = test.ttl
+		interval := feed.Schedule().Interval
+		if (interval > 0) != test.positive || interval < 0 {
+			t.Errorf("ttl %s: interval = %v", test.ttl, interval)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+-- name: UpsertEnclosure :exec
+INSERT INTO enclosures (post_id, url, mime_type, length, duration_seconds, episode, image_url)
+SELECT
+    posts.id,
+    sqlc.arg(url)::text,
+    sqlc.narg(mime_type)::text,
+    sqlc.narg(length)::bigint,
+    sqlc.narg(duration_seconds)::integer,
+    sqlc.narg(episode)::integer,
+    sqlc.narg(image_url)::text
+FROM posts
+WHERE posts.feed_id = sqlc.arg(feed_id) AND posts.guid = sqlc.arg(guid)
+ON CONFLICT (post_id, url) DO UPDATE
+SET mime_type = excluded.mime_type,
+    length = excluded.length,
+    duration_seconds = excluded.duration_seconds,
+    episode = excluded.episode,
+    image_url = excluded.image_url,
+    updated_at = now()
+WHERE (enclosures.mime_type, enclosures.length, enclosures.duration_seconds, enclosures.episode, enclosures.image_url)
+    IS DISTINCT FROM (excluded.mime_type, excluded.length, excluded.duration_seconds, excluded.episode, excluded.image_url);
+
+-- name: GetEnclosuresForPost :many
+SELECT * FROM enclosures
+WHERE post_id = $1
+ORDER BY created_at, url;
+
+-- name: GetPodcastEpisodesForUser :many
+SELECT
+    posts.id as post_id,
+    posts.title,
+    posts.published_at,
+    feeds.name as feed_name,
+    enclosures.url,
+    enclosures.mime_type,
+    enclosures.length,
+    enclosures.duration_seconds,
+    enclosures.episode,
+    COALESCE(post_states.read, false)::boolean as read
+FROM enclosures
+INNER JOIN posts on enclosures.post_id = posts.id
+INNER JOIN feeds on posts.feed_id = feeds.id
+INNER JOIN feed_follows on feed_follows.feed_id = feeds.id
+LEFT JOIN post_states on post_states.post_id = posts.id
+    AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (enclosures.mime_type IS NULL
+        OR enclosures.mime_type LIKE 'audio/%'
+        OR enclosures.mime_type LIKE 'video/%')
+    AND (NOT sqlc.arg(unread_only)::boolean OR post_states.read IS NOT TRUE)
+ORDER BY COALESCE(posts.published_at, posts.created_at) DESC, enclosures.created_at
+LIMIT sqlc.arg('limit');
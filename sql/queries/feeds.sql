@@ -7,6 +7,9 @@ VALUES (
 )
 RETURNING *;
 
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE feeds.id = $1;
+
 -- name: GetFeed :one
 SELECT * FROM feeds where feeds.url = $1;
 
@@ -40,26 +43,102 @@ JOIN feeds on inserted_feed_follow.feed_id = feeds.id;
 -- name: GetFeedFollowsForUser :many
 SELECT 
     users.name as user_name,
-    feeds.name as feed_name
+    feeds.name as feed_name,
+    feeds.url as feed_url,
+    (
+        SELECT count(*)
+        FROM posts
+        LEFT JOIN post_states on post_states.post_id = posts.id
+            AND post_states.user_id = feed_follows.user_id
+        WHERE posts.feed_id = feeds.id AND post_states.read IS NOT TRUE
+    ) as unread_count
 FROM feed_follows
 JOIN users on feed_follows.user_id = users.id
 JOIN feeds on feed_follows.feed_id = feeds.id
 WHERE feed_follows.user_id = $1;
 
 
+-- name: SetFeedFollowFolder :exec
+UPDATE feed_follows
+SET folder = $3, updated_at = now()
+WHERE feed_follows.user_id = $1 AND feed_follows.feed_id = $2;
+
+-- name: GetFollowedFeedsForUser :many
+SELECT
+    feeds.name,
+    feeds.url,
+    feeds.site_url,
+    feed_follows.folder
+FROM feed_follows
+JOIN feeds on feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feed_follows.folder NULLS FIRST, feeds.name;
+
 -- name: UnfollowFeed :exec
 DELETE FROM feed_follows
 WHERE feed_follows.user_id = $1 AND feed_follows.feed_id = $2;
 
--- name: MarkFeedFetched :exec
+-- name: ClaimNextFeedToFetch :one
+UPDATE feeds
+SET last_feteched = now(),
+    next_fetch_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::integer),
+    updated_at = now()
+WHERE feeds.id = (
+    SELECT id
+    FROM feeds
+    WHERE next_fetch_at IS NULL OR next_fetch_at <= now()
+    ORDER BY last_feteched NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateFeedCache :exec
 UPDATE feeds
-SET last_feteched = now(), updated_at = now()
+SET etag = $2, last_modified = $3, updated_at = now()
 WHERE feeds.id = $1;
 
--- name: GetNextFeedToFetch :one
-SELECT 
-    *
-FROM feeds
-ORDER by last_feteched NULLS FIRST
-LIMIT 1;
+-- name: RecordFeedSuccess :exec
+UPDATE feeds
+SET last_error = NULL,
+    consecutive_failures = 0,
+    last_success_at = now(),
+    next_fetch_at = $2,
+    updated_at = now()
+WHERE feeds.id = $1;
+
+-- name: RecordFeedFailure :exec
+UPDATE feeds
+SET last_error = $2,
+    consecutive_failures = consecutive_failures + 1,
+    next_fetch_at = $3,
+    updated_at = now()
+WHERE feeds.id = $1;
+
+-- name: GetFailingFeeds :many
+SELECT * FROM feeds
+WHERE consecutive_failures > 0
+ORDER BY consecutive_failures DESC, name;
+
+-- name: UpdateFeedSchedule :exec
+UPDATE feeds
+SET hinted_interval_seconds = $2,
+    skip_hours = $3,
+    skip_days = $4,
+    updated_at = now()
+WHERE feeds.id = $1;
 
+-- name: SetFeedIntervalOverride :exec
+UPDATE feeds
+SET interval_override_seconds = $2, updated_at = now()
+WHERE feeds.id = $1;
+
+-- name: SetFeedAdaptiveInterval :exec
+UPDATE feeds
+SET adaptive_interval_seconds = $2, updated_at = now()
+WHERE feeds.id = $1;
+
+-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET site_url = $2, updated_at = now()
+WHERE feeds.id = $1;
@@ -0,0 +1,21 @@
+-- name: StarPost :exec
+INSERT INTO post_stars (user_id, post_id, note)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET note = EXCLUDED.note, updated_at = now();
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars
+WHERE post_stars.user_id = $1 AND post_stars.post_id = $2;
+
+-- name: GetStarredPostsForUser :many
+SELECT
+    posts.*,
+    feeds.name as feed_name,
+    post_stars.note,
+    post_stars.created_at as starred_at
+FROM post_stars
+INNER JOIN posts on post_stars.post_id = posts.id
+INNER JOIN feeds on posts.feed_id = feeds.id
+WHERE post_stars.user_id = $1
+ORDER BY post_stars.created_at DESC;
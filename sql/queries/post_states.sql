@@ -0,0 +1,35 @@
+-- name: MarkPostRead :exec
+INSERT INTO post_states (user_id, post_id, read, read_at)
+VALUES ($1, $2, true, now())
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, read_at = now(), updated_at = now();
+
+-- name: MarkPostUnread :exec
+INSERT INTO post_states (user_id, post_id, read, read_at)
+VALUES ($1, $2, false, NULL)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = false, read_at = NULL, updated_at = now();
+
+-- name: MarkFeedRead :execrows
+INSERT INTO post_states (user_id, post_id, read, read_at)
+SELECT sqlc.arg(user_id)::uuid, posts.id, true, now()
+FROM posts
+WHERE posts.feed_id = sqlc.arg(feed_id)
+    AND EXISTS (
+        SELECT 1 FROM feed_follows
+        WHERE feed_follows.user_id = sqlc.arg(user_id) AND feed_follows.feed_id = posts.feed_id
+    )
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, read_at = now(), updated_at = now()
+WHERE post_states.read = false;
+
+-- name: MarkPostsReadBefore :execrows
+INSERT INTO post_states (user_id, post_id, read, read_at)
+SELECT sqlc.arg(user_id)::uuid, posts.id, true, now()
+FROM posts
+INNER JOIN feed_follows on feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND COALESCE(posts.published_at, posts.created_at) < sqlc.arg(before)::timestamp
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read = true, read_at = now(), updated_at = now()
+WHERE post_states.read = false;
@@ -1,21 +1,96 @@
--- name: CreatePost :one
-INSERT INTO posts (title, url, description, published_at, feed_id)
-VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5
-)
-RETURNING *;
-
 -- name: GetPostsForUser :many
 SELECT 
     posts.*,
-    feeds.name as feed_name
+    feeds.name as feed_name,
+    feeds.url as feed_url,
+    COALESCE(post_states.read, false)::boolean as read
 FROM posts
 INNER JOIN feeds on posts.feed_id = feeds.id
 INNER JOIN feed_follows on feed_follows.feed_id = feeds.id
-WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC
+LEFT JOIN post_states on post_states.post_id = posts.id
+    AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (NOT sqlc.arg(unread_only)::boolean OR post_states.read IS NOT TRUE)
+ORDER BY COALESCE(posts.published_at, posts.created_at) DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetFeedPublishDates :many
+SELECT published_at
+FROM posts
+WHERE feed_id = $1 AND published_at IS NOT NULL
+ORDER BY published_at DESC
 LIMIT $2;
+
+-- name: DeletePostsOlderThan :execrows
+DELETE FROM posts
+WHERE COALESCE(posts.published_at, posts.created_at) < sqlc.arg(before)::timestamp
+    AND NOT EXISTS (
+        SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id
+    );
+
+-- name: SearchPosts :many
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.published_at,
+    feeds.name as feed_name,
+    ts_rank(post_search_vector(posts.title, posts.description), query)::real as rank,
+    ts_headline(
+        'english',
+        posts.title || ' ' || coalesce(posts.description, ''),
+        query,
+        'StartSel=**, StopSel=**, MinWords=15, MaxWords=35'
+    )::text as snippet
+FROM posts
+INNER JOIN feeds on posts.feed_id = feeds.id,
+    websearch_to_tsquery('english', sqlc.arg(query)::text) query
+WHERE post_search_vector(posts.title, posts.description) @@ query
+    AND (
+        sqlc.arg(all_feeds)::boolean
+        OR EXISTS (
+            SELECT 1 FROM feed_follows
+            WHERE feed_follows.feed_id = posts.feed_id
+                AND feed_follows.user_id = sqlc.arg(user_id)
+        )
+    )
+ORDER BY rank DESC, COALESCE(posts.published_at, posts.created_at) DESC
+LIMIT sqlc.arg('limit');
+
+-- name: AdoptLegacyPost :exec
+-- AdoptLegacyPost gives the post stored under its url before posts had
+-- guids the item's guid, unless a post already has that guid.
+UPDATE posts
+SET guid = sqlc.arg(guid)
+WHERE posts.feed_id = sqlc.arg(feed_id)
+    AND posts.url = sqlc.arg(url)
+    AND posts.guid = posts.url
+    AND NOT EXISTS (
+        SELECT 1 FROM posts existing
+        WHERE existing.feed_id = sqlc.arg(feed_id) AND existing.guid = sqlc.arg(guid)
+    );
+
+-- name: UpsertPost :one
+-- UpsertPost returns sql.ErrNoRows when the post is already stored unchanged.
+INSERT INTO posts (title, url, description, published_at, feed_id, guid, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (feed_id, guid) DO UPDATE
+SET title = excluded.title,
+    url = excluded.url,
+    description = excluded.description,
+    content = excluded.content,
+    published_at = COALESCE(excluded.published_at, posts.published_at),
+    updated_at = now()
+WHERE posts.title IS DISTINCT FROM excluded.title
+    OR posts.url IS DISTINCT FROM excluded.url
+    OR posts.description IS DISTINCT FROM excluded.description
+    OR posts.content IS DISTINCT FROM excluded.content
+RETURNING *;
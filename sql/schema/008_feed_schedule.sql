@@ -0,0 +1,13 @@
+-- +goose Up
+-- skip_hours and skip_days are bitmasks: bit n is hour n (GMT) or weekday n
+-- (Sunday = 0) in which the feed asks not to be fetched.
+ALTER TABLE feeds ADD COLUMN hinted_interval_seconds INTEGER;
+ALTER TABLE feeds ADD COLUMN interval_override_seconds INTEGER;
+ALTER TABLE feeds ADD COLUMN skip_hours INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN skip_days INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN skip_days;
+ALTER TABLE feeds DROP COLUMN skip_hours;
+ALTER TABLE feeds DROP COLUMN interval_override_seconds;
+ALTER TABLE feeds DROP COLUMN hinted_interval_seconds;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- Posts are identified within their feed by the item's guid, or its
+-- normalized link when it has none. Existing posts keep their url.
+ALTER TABLE posts ADD COLUMN guid TEXT;
+UPDATE posts SET guid = url;
+ALTER TABLE posts ALTER COLUMN guid SET NOT NULL;
+ALTER TABLE posts DROP CONSTRAINT posts_url_key;
+ALTER TABLE posts ADD CONSTRAINT posts_feed_id_guid_key UNIQUE (feed_id, guid);
+
+-- +goose Down
+-- url is unique again, so posts sharing a url are merged into the newest
+-- of them. Read states and stars of the others move to the kept post
+-- first, unless the user has one on the kept post already; among several,
+-- the most recently updated moves.
+CREATE TEMPORARY TABLE post_merges ON COMMIT DROP AS
+SELECT id, keep_id
+FROM (
+    SELECT id, first_value(id) OVER (PARTITION BY url ORDER BY created_at DESC, id) AS keep_id
+    FROM posts
+) merges
+WHERE id <> keep_id;
+
+INSERT INTO post_states (user_id, post_id, created_at, updated_at, read, read_at)
+SELECT DISTINCT ON (post_states.user_id, post_merges.keep_id)
+    post_states.user_id, post_merges.keep_id, post_states.created_at,
+    post_states.updated_at, post_states.read, post_states.read_at
+FROM post_states
+INNER JOIN post_merges ON post_states.post_id = post_merges.id
+ORDER BY post_states.user_id, post_merges.keep_id, post_states.updated_at DESC
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+INSERT INTO post_stars (user_id, post_id, created_at, updated_at, note)
+SELECT DISTINCT ON (post_stars.user_id, post_merges.keep_id)
+    post_stars.user_id, post_merges.keep_id, post_stars.created_at,
+    post_stars.updated_at, post_stars.note
+FROM post_stars
+INNER JOIN post_merges ON post_stars.post_id = post_merges.id
+ORDER BY post_stars.user_id, post_merges.keep_id, post_stars.updated_at DESC
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+DELETE FROM posts WHERE id IN (SELECT id FROM post_merges);
+ALTER TABLE posts DROP CONSTRAINT posts_feed_id_guid_key;
+ALTER TABLE posts ADD CONSTRAINT posts_url_key UNIQUE (url);
+ALTER TABLE posts DROP COLUMN guid;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- content holds the full article (content:encoded, Atom content) where
+-- description may only be a summary.
+ALTER TABLE posts ADD COLUMN content TEXT;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN content;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Media attached to posts: RSS <enclosure>, Atom enclosure links and Media
+-- RSS content, with the iTunes episode details of the item.
+CREATE TABLE enclosures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    mime_type TEXT,
+    length BIGINT,
+    duration_seconds INTEGER,
+    episode INTEGER,
+    image_url TEXT,
+    UNIQUE (post_id, url)
+);
+
+-- +goose Down
+DROP TABLE enclosures;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Fetch times are instants computed in Go. As TIMESTAMP they were stored
+-- as wall time in whatever zone the client sent and compared against
+-- now() in the session time zone. next_fetch_at was written in UTC on
+-- success; the other two were only ever set by now().
+ALTER TABLE feeds
+    ALTER COLUMN last_feteched TYPE TIMESTAMPTZ,
+    ALTER COLUMN last_success_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN next_fetch_at TYPE TIMESTAMPTZ USING next_fetch_at AT TIME ZONE 'UTC';
+
+-- +goose Down
+ALTER TABLE feeds
+    ALTER COLUMN next_fetch_at TYPE TIMESTAMP USING next_fetch_at AT TIME ZONE 'UTC',
+    ALTER COLUMN last_success_at TYPE TIMESTAMP,
+    ALTER COLUMN last_feteched TYPE TIMESTAMP;
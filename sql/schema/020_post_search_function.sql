@@ -0,0 +1,25 @@
+-- +goose Up
+-- The search vector is indexed as an expression instead of stored in
+-- posts, so that queries returning posts.* do not carry a tsvector.
+-- +goose StatementBegin
+CREATE FUNCTION post_search_vector(title TEXT, description TEXT) RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+    SELECT setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+$$;
+-- +goose StatementEnd
+
+DROP INDEX posts_search_vector_idx;
+ALTER TABLE posts DROP COLUMN search_vector;
+CREATE INDEX posts_search_idx ON posts USING GIN (post_search_vector(title, description));
+
+-- +goose Down
+DROP INDEX posts_search_idx;
+ALTER TABLE posts ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX posts_search_vector_idx ON posts USING GIN (search_vector);
+DROP FUNCTION post_search_vector(TEXT, TEXT);
@@ -0,0 +1,8 @@
+// Package schema embeds the PostgreSQL migrations so gator can apply them
+// itself.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,137 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Posts are identified within their feed by the item's guid, or its
+-- normalized link when it has none. Existing posts keep their url.
+-- SQLite cannot drop the UNIQUE constraint on url, so posts is rebuilt,
+-- with foreign keys off so that dropping the old table does not cascade
+-- to post_states and post_stars. seq is kept, which keeps posts_fts valid.
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE posts_new (
+    seq INTEGER PRIMARY KEY,
+    id TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    description TEXT,
+    published_at TIMESTAMP,
+    feed_id TEXT NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    guid TEXT NOT NULL,
+    UNIQUE (feed_id, guid)
+);
+
+INSERT INTO posts_new (seq, id, created_at, updated_at, title, url, description, published_at, feed_id, guid)
+SELECT seq, id, created_at, updated_at, title, url, description, published_at, feed_id, url
+FROM posts;
+
+DROP TABLE posts;
+ALTER TABLE posts_new RENAME TO posts;
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts (rowid, title, description)
+    VALUES (new.seq, new.title, coalesce(new.description, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+    INSERT INTO posts_fts (posts_fts, rowid, title, description)
+    VALUES ('delete', old.seq, old.title, coalesce(old.description, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, description ON posts BEGIN
+    INSERT INTO posts_fts (posts_fts, rowid, title, description)
+    VALUES ('delete', old.seq, old.title, coalesce(old.description, ''));
+    INSERT INTO posts_fts (rowid, title, description)
+    VALUES (new.seq, new.title, coalesce(new.description, ''));
+END;
+-- +goose StatementEnd
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+-- url is unique again, so posts sharing a url are merged into the newest
+-- of them. Read states and stars of the others move to the kept post
+-- first, unless the user has one on the kept post already; among several,
+-- the most recently updated moves.
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TEMPORARY TABLE post_merges AS
+SELECT id, keep_id
+FROM (
+    SELECT id, first_value(id) OVER (PARTITION BY url ORDER BY created_at DESC, id) AS keep_id
+    FROM posts
+)
+WHERE id <> keep_id;
+
+INSERT OR IGNORE INTO post_states (user_id, post_id, created_at, updated_at, read, read_at)
+SELECT post_states.user_id, post_merges.keep_id, post_states.created_at,
+    post_states.updated_at, post_states.read, post_states.read_at
+FROM post_states
+INNER JOIN post_merges ON post_states.post_id = post_merges.id
+ORDER BY post_states.updated_at DESC;
+
+INSERT OR IGNORE INTO post_stars (user_id, post_id, created_at, updated_at, note)
+SELECT post_stars.user_id, post_merges.keep_id, post_stars.created_at,
+    post_stars.updated_at, post_stars.note
+FROM post_stars
+INNER JOIN post_merges ON post_stars.post_id = post_merges.id
+ORDER BY post_stars.updated_at DESC;
+
+CREATE TABLE posts_old (
+    seq INTEGER PRIMARY KEY,
+    id TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL UNIQUE,
+    description TEXT,
+    published_at TIMESTAMP,
+    feed_id TEXT NOT NULL REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+INSERT INTO posts_old (seq, id, created_at, updated_at, title, url, description, published_at, feed_id)
+SELECT seq, id, created_at, updated_at, title, url, description, published_at, feed_id
+FROM posts
+WHERE id NOT IN (SELECT id FROM post_merges);
+
+DROP TABLE posts;
+ALTER TABLE posts_old RENAME TO posts;
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts (rowid, title, description)
+    VALUES (new.seq, new.title, coalesce(new.description, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+    INSERT INTO posts_fts (posts_fts, rowid, title, description)
+    VALUES ('delete', old.seq, old.title, coalesce(old.description, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, description ON posts BEGIN
+    INSERT INTO posts_fts (posts_fts, rowid, title, description)
+    VALUES ('delete', old.seq, old.title, coalesce(old.description, ''));
+    INSERT INTO posts_fts (rowid, title, description)
+    VALUES (new.seq, new.title, coalesce(new.description, ''));
+END;
+-- +goose StatementEnd
+
+DELETE FROM post_states WHERE post_id NOT IN (SELECT id FROM posts);
+DELETE FROM post_stars WHERE post_id NOT IN (SELECT id FROM posts);
+INSERT INTO posts_fts (posts_fts) VALUES ('rebuild');
+DROP TABLE post_merges;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
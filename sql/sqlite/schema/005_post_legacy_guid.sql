@@ -0,0 +1,7 @@
+-- +goose Up
+-- Finds posts still keyed by their url from before guids were tracked,
+-- which AdoptLegacyPost looks up for every item fetched.
+CREATE INDEX posts_legacy_url_idx ON posts (feed_id, url) WHERE guid = url;
+
+-- +goose Down
+DROP INDEX posts_legacy_url_idx;